@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultAlbumTimeout is how long messages of an album are collected when Bot.AlbumTimeout is zero.
+const DefaultAlbumTimeout = time.Second
+
+// AlbumHandler represents a function that handles every message of an album at once. The updates
+// are ordered by message ID.
+type AlbumHandler func(ctx context.Context, b *Bot, updates []*UpdateResponse)
+
+// SetAlbumHandler will register a handler for albums. Messages which share a media_group_id are
+// then collected until no new one arrives for AlbumTimeout, and passed to the AlbumHandler
+// together instead of being dispatched one by one. A message arriving after its album was handled
+// is dispatched on its own.
+func (b *Bot) SetAlbumHandler(ah AlbumHandler) {
+	b.AlbumHandler = ah
+}
+
+// FlushAlbums passes every album that is still being collected to the AlbumHandler right away.
+// StartPolling calls it when polling stops; a webhook server should call it before it shuts down.
+func (b *Bot) FlushAlbums() {
+	if b.albums != nil {
+		b.albums.flushAll(b)
+	}
+}
+
+// albumTombstoneTTL is how long the ID of a handled album is remembered, so messages of the
+// album that arrive late don't start a new one.
+const albumTombstoneTTL = time.Minute
+
+// albumCollector buffers the messages of albums until they are complete. It is safe for
+// concurrent use, since webhook deliveries of one album may overlap.
+type albumCollector struct {
+	mutex   sync.Mutex
+	albums  map[string]*pendingAlbum
+	flushed map[string]time.Time // when albums were handled
+}
+
+type pendingAlbum struct {
+	ctx     context.Context
+	updates []*UpdateResponse
+	timer   *time.Timer
+}
+
+func newAlbumCollector() *albumCollector {
+	return &albumCollector{
+		albums:  make(map[string]*pendingAlbum),
+		flushed: make(map[string]time.Time),
+	}
+}
+
+// add buffers ur until its album is complete. An album is complete when no message was added for
+// the timeout, or when it has the maximum number of items. add returns false if the album of ur
+// was already handled, so ur should be dispatched like any other message.
+func (c *albumCollector) add(ctx context.Context, b *Bot, ur *UpdateResponse) bool {
+	timeout := b.AlbumTimeout
+	if timeout <= 0 {
+		timeout = DefaultAlbumTimeout
+	}
+
+	key := strconv.FormatInt(ur.Message.Chat.ID, 10) + ":" + ur.Message.MediaGroupID
+	now := time.Now()
+
+	c.mutex.Lock()
+	if t, ok := c.flushed[key]; ok && now.Sub(t) < albumTombstoneTTL {
+		c.mutex.Unlock()
+		return false
+	}
+
+	a, ok := c.albums[key]
+	if !ok {
+		c.sweep(now)
+
+		// the album is handled after the webhook request ended, so only keep the context's values
+		a = &pendingAlbum{ctx: context.WithoutCancel(ctx)}
+		a.timer = time.AfterFunc(timeout, func() {
+			c.flush(b, key, a)
+		})
+		c.albums[key] = a
+	} else {
+		a.timer.Reset(timeout)
+	}
+
+	a.updates = append(a.updates, ur)
+	full := len(a.updates) >= MaxMediaGroupSize
+	c.mutex.Unlock()
+
+	if full && a.timer.Stop() {
+		c.flush(b, key, a)
+	}
+
+	return true
+}
+
+// flushAll flushes every album whose timer hasn't fired yet.
+func (c *albumCollector) flushAll(b *Bot) {
+	c.mutex.Lock()
+	pending := make(map[string]*pendingAlbum, len(c.albums))
+	for key, a := range c.albums {
+		if a.timer.Stop() {
+			pending[key] = a
+		}
+	}
+	c.mutex.Unlock()
+
+	for key, a := range pending {
+		c.flush(b, key, a)
+	}
+}
+
+// flush passes the album to the AlbumHandler, unless it was already flushed.
+func (c *albumCollector) flush(b *Bot, key string, a *pendingAlbum) {
+	c.mutex.Lock()
+	if c.albums[key] != a {
+		c.mutex.Unlock()
+		return
+	}
+
+	delete(c.albums, key)
+	c.flushed[key] = time.Now()
+	updates := a.updates
+	c.mutex.Unlock()
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Message.ID < updates[j].Message.ID
+	})
+
+	if ah := b.AlbumHandler; ah != nil {
+		ah(a.ctx, b, updates)
+	}
+}
+
+// sweep forgets albums that were handled longer than albumTombstoneTTL ago.
+func (c *albumCollector) sweep(now time.Time) {
+	for key, t := range c.flushed {
+		if now.Sub(t) >= albumTombstoneTTL {
+			delete(c.flushed, key)
+		}
+	}
+}
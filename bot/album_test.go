@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func albumUpdate(messageID int64, mediaGroupID string) *UpdateResponse {
+	return &UpdateResponse{
+		Message: &Message{
+			ID:           messageID,
+			From:         &User{ID: 1},
+			Chat:         &Chat{ID: 2, Type: ChatTypePrivate},
+			MediaGroupID: mediaGroupID,
+		},
+	}
+}
+
+func TestAlbumHandler(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	b.AlbumTimeout = 50 * time.Millisecond
+
+	albums := make(chan []*UpdateResponse, 2)
+	b.SetAlbumHandler(func(ctx context.Context, b *Bot, updates []*UpdateResponse) {
+		albums <- updates
+	})
+
+	defaultCalls := 0
+	b.SetDefaultHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		defaultCalls++
+	})
+
+	// webhook deliveries of one album may arrive concurrently and out of order
+	var wg sync.WaitGroup
+	for _, id := range []int64{13, 11, 12} {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			assert.NoError(t, b.Dispatch(context.Background(), albumUpdate(id, "album-1")))
+		}(id)
+	}
+	wg.Wait()
+
+	assert.NoError(t, b.Dispatch(context.Background(), albumUpdate(20, "")))
+	assert.Equal(t, 1, defaultCalls, "messages outside of an album are dispatched right away")
+
+	select {
+	case updates := <-albums:
+		var ids []int64
+		for _, ur := range updates {
+			ids = append(ids, ur.Message.ID)
+		}
+		assert.Equal(t, []int64{11, 12, 13}, ids)
+	case <-time.After(5 * time.Second):
+		t.Fatal("album was not handled")
+	}
+
+	select {
+	case <-albums:
+		t.Fatal("album was handled twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAlbumHandlerFull(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	b.AlbumTimeout = time.Hour
+
+	albums := make(chan []*UpdateResponse, 1)
+	b.SetAlbumHandler(func(ctx context.Context, b *Bot, updates []*UpdateResponse) {
+		albums <- updates
+	})
+
+	for i := 1; i <= MaxMediaGroupSize; i++ {
+		assert.NoError(t, b.Dispatch(context.Background(), albumUpdate(int64(i), "album-2")))
+	}
+
+	select {
+	case updates := <-albums:
+		assert.Len(t, updates, MaxMediaGroupSize, "a full album doesn't wait for the timeout")
+	case <-time.After(5 * time.Second):
+		t.Fatal("album was not handled")
+	}
+}
+
+func TestAlbumHandlerLateMessage(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	b.AlbumTimeout = 10 * time.Millisecond
+
+	albums := make(chan []*UpdateResponse, 2)
+	b.SetAlbumHandler(func(ctx context.Context, b *Bot, updates []*UpdateResponse) {
+		albums <- updates
+	})
+
+	var late []int64
+	b.SetDefaultHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		late = append(late, ur.Message.ID)
+	})
+
+	assert.NoError(t, b.Dispatch(context.Background(), albumUpdate(1, "album-3")))
+	assert.NoError(t, b.Dispatch(context.Background(), albumUpdate(2, "album-3")))
+
+	select {
+	case updates := <-albums:
+		assert.Len(t, updates, 2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("album was not handled")
+	}
+
+	// a message of the album arriving after the timeout doesn't start another album
+	assert.NoError(t, b.Dispatch(context.Background(), albumUpdate(3, "album-3")))
+	assert.Equal(t, []int64{3}, late)
+
+	select {
+	case <-albums:
+		t.Fatal("a late message started a second album")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFlushAlbums(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	b.AlbumTimeout = time.Hour
+
+	var handled [][]*UpdateResponse
+	b.SetAlbumHandler(func(ctx context.Context, b *Bot, updates []*UpdateResponse) {
+		handled = append(handled, updates)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, b.Dispatch(ctx, albumUpdate(1, "album-4")))
+	assert.NoError(t, b.Dispatch(ctx, albumUpdate(2, "album-4")))
+	cancel()
+
+	// e.g. when polling stopped, albums are handled without waiting for the timeout
+	b.FlushAlbums()
+	if assert.Len(t, handled, 1) {
+		assert.Len(t, handled[0], 2)
+	}
+
+	b.FlushAlbums()
+	assert.Len(t, handled, 1)
+}
@@ -3,60 +3,128 @@ package bot
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
-	"mime/multipart"
 	"net/http"
-	"os"
+	"net/url"
 	"path/filepath"
 	"regexp"
-	"strconv"
+	"strings"
+	"time"
 )
 
 // Bot represents a Telegram bot.
 type Bot struct {
-	BotName                string
-	Token                  string
-	CommandHandlers        map[string]Handler
-	CommandPatternHandlers map[*regexp.Regexp]PatternHandler
-	SessionHandlers        map[int]SessionHandler
-	DefaultHandler         Handler
-	BeforeCommandCallback  Callback
-	Debug                  bool
-	Session                Session
+	BotName                 string
+	Token                   string
+	CommandHandlers         map[string]Handler
+	CommandPatternHandlers  map[*regexp.Regexp]PatternHandler
+	SessionHandlers         map[int]SessionHandler
+	CallbackHandlers        map[string]CallbackQueryHandler
+	CallbackPatternHandlers map[*regexp.Regexp]CallbackQueryPatternHandler
+	DefaultHandler          Handler
+	BeforeCommandCallback   Callback
+	Debug                   bool
+	Session                 Session
+	ErrorHandler            ErrorHandler
+	SecretToken             string
+	RetryPolicy             *RetryPolicy
+	RateLimiter             *RateLimiter
+	FileCache               FileCache
+	AlbumHandler            AlbumHandler
+
+	// Handlers for updates which aren't new messages. The message is in ur.EditedMessage,
+	// ur.ChannelPost or ur.EditedChannelPost, or ur.EffectiveMessage().
+	EditedMessageHandler     Handler
+	ChannelPostHandler       Handler
+	EditedChannelPostHandler Handler
+
+	// DispatchEditedCommands passes edited commands to the command handlers again, e.g. so a
+	// user can fix a typo in a command. Like commands in channel posts, they have no ur.Message,
+	// so command handlers have to use ur.EffectiveMessage().
+	DispatchEditedCommands bool
+
+	// Handlers for inline mode. The query is in ur.InlineQuery, the chosen result in
+	// ur.ChosenInlineResult.
+	InlineQueryHandler        Handler
+	ChosenInlineResultHandler Handler
+
+	// Handlers for changes of chat members and for join requests. The change is in
+	// ur.MyChatMember or ur.ChatMember, the request in ur.ChatJoinRequest.
+	MyChatMemberHandler    Handler
+	ChatMemberHandler      Handler
+	ChatJoinRequestHandler Handler
+
+	// Handlers for polls the bot sent. The poll is in ur.Poll, the vote in ur.PollAnswer.
+	PollHandler       Handler
+	PollAnswerHandler Handler
+
+	// PollTimeout is the long polling timeout used by StartPolling. Defaults to DefaultPollTimeout.
+	PollTimeout time.Duration
+
+	// MaxBodySize caps the webhook request body read by ServeHTTP. Defaults to DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// AlbumTimeout is how long to wait for more messages of an album before it is passed to the
+	// AlbumHandler. Defaults to DefaultAlbumTimeout.
+	AlbumTimeout time.Duration
 
 	botDirectMsgRegex *regexp.Regexp
-
-	// allow us to inject a client for testing
-	client *http.Client
+	albums            *albumCollector
+	callbacks         callbackAnswers
+	sleep             func(ctx context.Context, d time.Duration) bool // replaces wait in tests
+
+	// set with the Options passed to New
+	client          *http.Client
+	baseURL         string
+	fileBaseURL     string
+	testEnvironment bool
+	localServer     bool
 }
 
-// Handler represents a function that can handle an update from Telegram.
-type Handler func(b *Bot, ur *UpdateResponse, args string)
+// Handler represents a function that can handle an update from Telegram. The context is cancelled
+// when the webhook request is cancelled or polling stops. Only a new message sets ur.Message.
+// Command handlers also get commands in channel posts, and edited commands if
+// DispatchEditedCommands is set, so they should use ur.EffectiveMessage() instead.
+type Handler func(ctx context.Context, b *Bot, ur *UpdateResponse, args string)
 
 // PatternHandler represents a function that can handle an update from Telegram.
-type PatternHandler func(b *Bot, ur *UpdateResponse, matches []string)
+type PatternHandler func(ctx context.Context, b *Bot, ur *UpdateResponse, matches []string)
 
 // SessionHandler represents a function that can handle an update from Telegram with a session active.
-type SessionHandler func(b *Bot, ur *UpdateResponse, s SessionRecord)
+type SessionHandler func(ctx context.Context, b *Bot, ur *UpdateResponse, s SessionRecord)
 
 // Callback represents a function that can handle a callback.
-type Callback func(b *Bot, ur *UpdateResponse)
+type Callback func(ctx context.Context, b *Bot, ur *UpdateResponse)
 
 // New instantiates a new Telegram instance.
-func New(botName, token string) *Bot {
-	return &Bot{
-		BotName:                botName,
-		Token:                  token,
-		CommandHandlers:        make(map[string]Handler),
-		CommandPatternHandlers: make(map[*regexp.Regexp]PatternHandler),
-		SessionHandlers:        make(map[int]SessionHandler),
-		botDirectMsgRegex:      regexp.MustCompile(fmt.Sprintf("^@%s\\s+", botName)),
-		client:                 http.DefaultClient,
+func New(botName, token string, opts ...Option) *Bot {
+	b := &Bot{
+		BotName:                 botName,
+		Token:                   token,
+		CommandHandlers:         make(map[string]Handler),
+		CommandPatternHandlers:  make(map[*regexp.Regexp]PatternHandler),
+		SessionHandlers:         make(map[int]SessionHandler),
+		CallbackHandlers:        make(map[string]CallbackQueryHandler),
+		CallbackPatternHandlers: make(map[*regexp.Regexp]CallbackQueryPatternHandler),
+		botDirectMsgRegex:       regexp.MustCompile(fmt.Sprintf("^@%s\\s+", botName)),
+		albums:                  newAlbumCollector(),
+		client:                  http.DefaultClient,
+		baseURL:                 DefaultBaseURL,
+	}
+
+	for _, opt := range opts {
+		opt(b)
 	}
+
+	if b.fileBaseURL == "" {
+		b.fileBaseURL = b.baseURL
+	}
+
+	return b
 }
 
 // AddCommandHandler will register a Handler with a specific command.
@@ -90,11 +158,38 @@ func (b *Bot) SetDefaultHandler(dh Handler) {
 	b.DefaultHandler = dh
 }
 
+// SetEditedMessageHandler will register a handler to be called when a message was edited.
+func (b *Bot) SetEditedMessageHandler(h Handler) {
+	b.EditedMessageHandler = h
+}
+
+// SetChannelPostHandler will register a handler to be called for a channel post which isn't a
+// command.
+func (b *Bot) SetChannelPostHandler(h Handler) {
+	b.ChannelPostHandler = h
+}
+
+// SetEditedChannelPostHandler will register a handler to be called when a channel post was edited.
+func (b *Bot) SetEditedChannelPostHandler(h Handler) {
+	b.EditedChannelPostHandler = h
+}
+
 // SetBeforeCommandCallback will set a callback which is executed before a command is executed.
 func (b *Bot) SetBeforeCommandCallback(cb Callback) {
 	b.BeforeCommandCallback = cb
 }
 
+// SetRetryPolicy sets the policy used to repeat failed API calls. A nil policy disables retries.
+func (b *Bot) SetRetryPolicy(p *RetryPolicy) {
+	b.RetryPolicy = p
+}
+
+// SetRateLimiter sets the limiter every send and edit call waits for. A nil limiter disables
+// throttling.
+func (b *Bot) SetRateLimiter(l *RateLimiter) {
+	b.RateLimiter = l
+}
+
 // SetSession sets the session object which is responsible for getting, setting, and deleting sessions.
 func (b *Bot) SetSession(s Session) {
 	b.Session = s
@@ -102,38 +197,61 @@ func (b *Bot) SetSession(s Session) {
 
 var cmdRegex = regexp.MustCompile("^(?i)/([a-z0-9_]+)(?:@([a-z0-9_]+))?(?:\\s+(.*))?\\z")
 
-// HandleUpdate will call an appropriate Handler depending on the UpdateResponse payload.
-// Attempts to find a command handler. If not found, attempts to find a session handler if there
-// is an active session. Finally the default handler is called.
+// HandleUpdate will decode the UpdateResponse from the request body and pass it to Dispatch.
+// If a secret token is set, requests without it are rejected with ErrInvalidSecretToken.
 func (b *Bot) HandleUpdate(r *http.Request) error {
+	if err := b.verifySecretToken(r); err != nil {
+		return err
+	}
+
 	d := json.NewDecoder(r.Body)
 	var ur UpdateResponse
 	if err := d.Decode(&ur); err != nil {
 		return err
 	}
 
-	if ur.Message == nil {
-		if ur.EditedMessage != nil {
-			if b.Debug {
-				log.Printf("edited message received, but I cannot handle this yet: %s\n", ur.String())
-			}
+	return b.Dispatch(r.Context(), &ur)
+}
 
-			return nil
-		} else if ur.ChannelPost != nil {
-			if b.Debug {
-				log.Printf("channel post received, but I cannot handle this yet: %s\n", ur.String())
-			}
+// Dispatch will call an appropriate Handler depending on the UpdateResponse payload.
+// Attempts to find a command handler. If not found, attempts to find a session handler if there
+// is an active session. Finally the default handler is called. Messages of an album are
+// collected for the AlbumHandler instead, if one is set. Callback queries are passed to the
+// callback handler registered for their data, while edited messages, channel posts, inline
+// queries, chat member updates and polls go to their own handlers.
+//
+// Dispatch may be used to feed updates from any source, such as a queue or a replay file.
+func (b *Bot) Dispatch(ctx context.Context, ur *UpdateResponse) error {
+	if chat := ur.EffectiveChat(); chat != nil && b.RateLimiter != nil {
+		b.RateLimiter.SetChatType(chat.ID, chat.Type)
+	}
 
-			return nil
-		} else if ur.EditedChannelPost != nil {
-			if b.Debug {
-				log.Printf("edited channel post received, but I cannot handle this yet: %s\n", ur.String())
-			}
+	if ur.CallbackQuery != nil {
+		return b.dispatchCallbackQuery(ctx, ur)
+	}
 
-			return nil
+	if ur.InlineQuery != nil || ur.ChosenInlineResult != nil {
+		return b.dispatchInline(ctx, ur)
+	}
+
+	if ur.MyChatMember != nil || ur.ChatMember != nil || ur.ChatJoinRequest != nil {
+		return b.dispatchChatMember(ctx, ur)
+	}
+
+	if ur.Poll != nil || ur.PollAnswer != nil {
+		return b.dispatchPoll(ctx, ur)
+	}
+
+	if ur.Message == nil {
+		if msg := ur.EffectiveMessage(); msg != nil {
+			return b.dispatchOther(ctx, ur, msg)
 		}
 
-		log.Printf("error: null message found: %s\n", ur.String())
+		if b.Debug {
+			log.Printf("unsupported update received in %s: %s\n", b.BotName, ur.String())
+		}
+
+		// reported by the caller, e.g. ServeHTTP or StartPolling
 		return errors.New("null message found")
 	}
 
@@ -142,26 +260,11 @@ func (b *Bot) HandleUpdate(r *http.Request) error {
 		log.Printf("received in %s: %s\n", b.BotName, copy)
 	}
 
-	if match := cmdRegex.FindStringSubmatch(ur.Message.Text); match != nil {
-		// It's a command, but it's not intended for our bot
-		if match[2] != "" && match[2] != b.BotName {
-			return nil
-		}
-
-		if cb := b.BeforeCommandCallback; cb != nil {
-			cb(b, &ur)
-		}
-
-		if h, ok := b.CommandHandlers[match[1]]; ok {
-			h(b, &ur, match[3])
-		} else {
-			for r, h := range b.CommandPatternHandlers {
-				if matches := r.FindStringSubmatch(match[1]); matches != nil {
-					h(b, &ur, matches)
-				}
-			}
-		}
+	if b.AlbumHandler != nil && ur.Message.MediaGroupID != "" && b.albums.add(ctx, b, ur) {
+		return nil
+	}
 
+	if b.dispatchCommand(ctx, ur, ur.Message) {
 		return nil
 	}
 
@@ -179,149 +282,286 @@ func (b *Bot) HandleUpdate(r *http.Request) error {
 			b.Session.DeleteSessionByAuthorIDAndChatID(ur.FromID(), ur.ChatID())
 
 			if h, ok := b.SessionHandlers[s.StateID()]; ok {
-				h(b, &ur, s)
+				h(ctx, b, ur, s)
 				return nil
 			}
 		}
 	}
 
 	if b.DefaultHandler != nil {
-		b.DefaultHandler(b, &ur, "")
+		b.DefaultHandler(ctx, b, ur, "")
 	}
 
 	return nil
 }
 
-// PostSendDocument will send a document and return the result from the server.
-func (b *Bot) PostSendDocument(document *SendDocument) error {
-	if document.Document == "" {
-		return errors.New("bot: Document not specified")
+// dispatchCommand calls the command handler if msg is a command. It returns false if msg isn't a
+// command.
+func (b *Bot) dispatchCommand(ctx context.Context, ur *UpdateResponse, msg *Message) bool {
+	match := cmdRegex.FindStringSubmatch(msg.Text)
+	if match == nil {
+		return false
 	}
 
-	file, err := os.Open(document.Document)
-	if err != nil {
-		return err
+	// It's a command, but it's not intended for our bot
+	if match[2] != "" && match[2] != b.BotName {
+		return true
 	}
-	defer file.Close()
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("document", filepath.Base(document.Document))
-	if err != nil {
-		return err
+	if cb := b.BeforeCommandCallback; cb != nil {
+		cb(ctx, b, ur)
 	}
-	if _, err := io.Copy(part, file); err != nil {
-		return err
+
+	if h, ok := b.CommandHandlers[match[1]]; ok {
+		h(ctx, b, ur, match[3])
+	} else {
+		for r, h := range b.CommandPatternHandlers {
+			if matches := r.FindStringSubmatch(match[1]); matches != nil {
+				h(ctx, b, ur, matches)
+			}
+		}
 	}
 
-	writer.WriteField("chat_id", strconv.FormatInt(document.ChatID, 10))
+	return true
+}
 
-	if document.ReplyToMessageID > 0 {
-		writer.WriteField("reply_to_message_id", strconv.FormatInt(document.ReplyToMessageID, 10))
+// dispatchOther handles edited messages and channel posts. Commands in channel posts are
+// dispatched like commands in messages, edited commands only if DispatchEditedCommands is set.
+func (b *Bot) dispatchOther(ctx context.Context, ur *UpdateResponse, msg *Message) error {
+	if b.Debug {
+		log.Printf("received in %s: %s\n", b.BotName, ur.String())
 	}
 
-	if document.ReplyMarkup != nil {
-		b, err := json.Marshal(document.ReplyMarkup)
-		if err != nil {
-			return err
-		}
+	var h Handler
+	commands := false
 
-		writer.WriteField("reply_markup", string(b))
+	switch {
+	case ur.EditedMessage != nil:
+		h, commands = b.EditedMessageHandler, b.DispatchEditedCommands
+	case ur.ChannelPost != nil:
+		h, commands = b.ChannelPostHandler, true
+	case ur.EditedChannelPost != nil:
+		h, commands = b.EditedChannelPostHandler, b.DispatchEditedCommands
 	}
 
-	if err := writer.Close(); err != nil {
-		return err
+	if commands && b.dispatchCommand(ctx, ur, msg) {
+		return nil
 	}
 
-	resp, err := b.client.Post(b.URL("sendDocument"), writer.FormDataContentType(), body)
-	if err != nil {
-		return err
+	if h != nil {
+		h(ctx, b, ur, "")
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func (b *Bot) genericPost(endpoint string, msg interface{}) (*MessageResult, error) {
+// PostSendDocument will send a document and return the result from the server.
+func (b *Bot) PostSendDocument(document *SendDocument) error {
+	return b.PostSendDocumentContext(context.Background(), document)
+}
+
+// PostSendDocumentContext is like PostSendDocument, but with a context.
+func (b *Bot) PostSendDocumentContext(ctx context.Context, document *SendDocument) error {
+	if document.Document == nil {
+		return errors.New("bot: Document not specified")
+	}
+
+	var result MessageResult
+	return b.postMedia(ctx, "sendDocument", document, &result)
+}
+
+// Call will call any Bot API method, including ones without a typed wrapper. params is encoded
+// as JSON, or as multipart/form-data if it implements MultipartEncoder. The "result" field of
+// Telegram's response is decoded into result, which may be nil. Unsuccessful calls return an
+// *APIError.
+//
+// Example:
+//
+//	var me User
+//	err := b.Call(ctx, "getMe", nil, &me)
+func (b *Bot) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if params == nil {
+		params = struct{}{}
+	}
+
+	var raw RawResult
+	if err := b.genericPost(ctx, method, params, &raw); err != nil {
+		return err
+	}
+
+	if result == nil || len(raw.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(raw.Result, result)
+}
+
+func (b *Bot) genericPost(ctx context.Context, endpoint string, msg interface{}, result apiResult) error {
+	if m, ok := msg.(MultipartEncoder); ok && needsMultipart(m) {
+		return b.multipartPost(ctx, endpoint, m, result)
+	}
+
 	bts := &bytes.Buffer{}
 	j := json.NewEncoder(bts)
 	if err := j.Encode(msg); err != nil {
-		return nil, err
+		return err
+	}
+
+	chatID, n := throttleTarget(msg)
+
+	body := bts.Bytes()
+	return b.do(ctx, endpoint, func() (*http.Request, error) {
+		if err := b.throttle(ctx, chatID, n); err != nil {
+			return nil, err
+		}
+
+		r, err := http.NewRequestWithContext(ctx, "POST", b.URL(endpoint), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		r.Header.Set("Content-Type", "application/json")
+		return r, nil
+	}, result)
+}
+
+// do sends the request built by newRequest and decodes Telegram's response into result. Failed
+// requests are repeated according to the RetryPolicy.
+func (b *Bot) do(ctx context.Context, endpoint string, newRequest func() (*http.Request, error), result apiResult) error {
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		err := b.roundTrip(endpoint, newRequest, result)
+		if err == nil {
+			return nil
+		}
+
+		if errors.Is(err, errNotReplayable) && lastErr != nil {
+			// the upload can't be repeated, so report why the last attempt failed
+			return lastErr
+		}
+		lastErr = err
+
+		wait, ok := b.RetryPolicy.retry(attempt, endpoint, err)
+		if !ok {
+			return err
+		}
+
+		if b.Debug {
+			log.Printf("retrying %s in %s: %s\n", endpoint, wait, err)
+		}
+
+		if !b.wait(ctx, wait) {
+			return err
+		}
 	}
+}
 
-	r, err := http.NewRequest("POST", b.URL(endpoint), bts)
+func (b *Bot) roundTrip(endpoint string, newRequest func() (*http.Request, error), result apiResult) error {
+	r, err := newRequest()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	r.Header.Set("Content-Type", "application/json")
+	body := r.Body
 	resp, err := b.client.Do(r)
+	err = b.redactToken(err)
+	if u, ok := body.(*uploadBody); ok {
+		// an error encoding the upload explains a failed request best
+		if uerr := u.finish(); uerr != nil {
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			return uerr
+		}
+	}
+
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
+	// clear any error left over from a previous attempt
+	*result.genericResult() = GenericResult{}
+
 	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(result); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			// e.g. an HTML error page from a proxy in front of the API
+			return &APIError{Method: endpoint, ErrorCode: resp.StatusCode, Description: resp.Status}
+		}
+
+		return err
+	}
+
+	if gr := result.genericResult(); !gr.OK {
+		return newAPIError(endpoint, gr)
+	}
+	return nil
+}
+
+func (b *Bot) postMessage(ctx context.Context, endpoint string, msg interface{}) (*MessageResult, error) {
 	var result MessageResult
-	if err := dec.Decode(&result); err != nil {
+	err := b.postMedia(ctx, endpoint, msg, &result)
+	if err != nil && result.ErrorCode == 0 {
+		// no response was decoded from Telegram
 		return nil, err
 	}
 
-	if !result.OK {
-		return &result, fmt.Errorf("bot: failed request to %s { %d, %s }", endpoint, result.ErrorCode, result.Description)
-	}
 	return &result, err
 }
 
 // PostSendMessage will send a message and return the result from the server.
 func (b *Bot) PostSendMessage(msg *SendMessage) (*MessageResult, error) {
-	return b.genericPost("sendMessage", msg)
+	return b.PostSendMessageContext(context.Background(), msg)
+}
+
+// PostSendMessageContext is like PostSendMessage, but with a context.
+func (b *Bot) PostSendMessageContext(ctx context.Context, msg *SendMessage) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendMessage", msg)
 }
 
 // PostEditMessageText will send a message and return the result from the server.
 func (b *Bot) PostEditMessageText(msg *EditMessageText) (*MessageResult, error) {
-	return b.genericPost("editMessageText", msg)
+	return b.PostEditMessageTextContext(context.Background(), msg)
 }
 
-// SetWebhook will post a message to Telegram's setWebhook method. This will allow the bot
-// to register with Telegram for notifications.
-func (b *Bot) SetWebhook(uri string, certFile string) error {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	if certFile != "" {
-		file, err := os.Open(certFile)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
+// PostEditMessageTextContext is like PostEditMessageText, but with a context.
+func (b *Bot) PostEditMessageTextContext(ctx context.Context, msg *EditMessageText) (*MessageResult, error) {
+	return b.postMessage(ctx, "editMessageText", msg)
+}
 
-		part, err := writer.CreateFormFile("certificate", filepath.Base(certFile))
-		if err != nil {
-			return err
-		}
+// URL returns the correct Telegram URL to use.
+func (b *Bot) URL(m string) string {
+	return b.baseURL + "/bot" + b.Token + b.environment() + "/" + m
+}
 
-		if _, err := io.Copy(part, file); err != nil {
-			return err
-		}
+// redactURL replaces the token in a URL built by URL or FileURL, so it can be logged.
+func (b *Bot) redactURL(u string) string {
+	if b.Token == "" {
+		return u
 	}
 
-	writer.WriteField("url", uri)
-
-	if err := writer.Close(); err != nil {
-		return err
-	}
+	return strings.ReplaceAll(u, b.Token, "<token>")
+}
 
-	resp, err := b.client.Post(b.URL("setWebhook"), writer.FormDataContentType(), body)
-	if err != nil {
-		return err
+// redactToken removes the token from the URL of a network error, which the http.Client reports
+// with the full URL of the request.
+func (b *Bot) redactToken(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = b.redactURL(urlErr.URL)
 	}
-	defer resp.Body.Close()
 
-	return nil
+	return err
 }
 
-// URL returns the correct Telegram URL to use.
-func (b *Bot) URL(m string) string {
-	return "https://api.telegram.org/bot" + b.Token + "/" + m
+// FileURL returns the URL to download a file from, given the file path returned by getFile.
+// With WithLocalServer, absolute paths are returned as a file:// URL.
+func (b *Bot) FileURL(filePath string) string {
+	if b.isLocalFile(filePath) {
+		return "file://" + filepath.ToSlash(filePath)
+	}
+
+	return b.fileBaseURL + "/file/bot" + b.Token + b.environment() + "/" + strings.TrimLeft(filePath, "/")
 }
@@ -1,8 +1,12 @@
 package bot
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"regexp"
 	"strings"
@@ -21,7 +25,7 @@ type capture struct {
 	deleteHandlerCalled   bool
 }
 
-func (c *capture) helpHandler(b *Bot, u *UpdateResponse, args string) {
+func (c *capture) helpHandler(ctx context.Context, b *Bot, u *UpdateResponse, args string) {
 	c.helpHandlerCalled = true
 	c.callCount += 1
 
@@ -30,7 +34,7 @@ func (c *capture) helpHandler(b *Bot, u *UpdateResponse, args string) {
 	assert.Equal(c.t, "/help@Test_Bot my options", u.Message.Text)
 }
 
-func (c *capture) deleteHandler(b *Bot, u *UpdateResponse, matches []string) {
+func (c *capture) deleteHandler(ctx context.Context, b *Bot, u *UpdateResponse, matches []string) {
 	c.deleteHandlerCalled = true
 	c.callCount += 1
 
@@ -39,7 +43,7 @@ func (c *capture) deleteHandler(b *Bot, u *UpdateResponse, matches []string) {
 	assert.Equal(c.t, "/delete1234@Test_Bot", u.Message.Text)
 }
 
-func (c *capture) sessionHandler(b *Bot, u *UpdateResponse, s SessionRecord) {
+func (c *capture) sessionHandler(ctx context.Context, b *Bot, u *UpdateResponse, s SessionRecord) {
 	c.sessionHandlerCalled = true
 	c.callCount += 1
 
@@ -47,14 +51,14 @@ func (c *capture) sessionHandler(b *Bot, u *UpdateResponse, s SessionRecord) {
 	assert.Equal(c.t, "this is my data", s.Data())
 }
 
-func (c *capture) callbackHandler(b *Bot, u *UpdateResponse) {
+func (c *capture) callbackHandler(ctx context.Context, b *Bot, u *UpdateResponse) {
 	c.callbackHandlerCalled = true
 	c.callCount += 1
 
 	assert.Equal(c.t, 1, c.callCount)
 }
 
-func (c *capture) defaultHandler(b *Bot, u *UpdateResponse, args string) {
+func (c *capture) defaultHandler(ctx context.Context, b *Bot, u *UpdateResponse, args string) {
 	c.defaultHandlerCalled = true
 	c.callCount += 1
 
@@ -184,6 +188,16 @@ func (rt *testRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
 	body := strings.NewReader(rt.response)
 	bodyCloser := ioutil.NopCloser(body)
 
+	// read the request body like a server would, since uploads are streamed
+	if r.Body != nil {
+		requestBody, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
 	rt.request = r
 
 	response := &http.Response{
@@ -343,3 +357,161 @@ func (s *testSession) SessionByAuthorIDAndChatID(authorID, chatID int64) (Sessio
 
 	return r, nil
 }
+
+func TestDispatch(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	var args string
+	b.AddCommandHandler("echo", func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		args = a
+		assert.Equal(t, "value", ctx.Value(key{}))
+	})
+
+	ur := &UpdateResponse{
+		UpdateID: 1,
+		Message: &Message{
+			ID:   2,
+			From: &User{ID: 3},
+			Chat: &Chat{ID: 4, Type: ChatTypePrivate},
+			Text: "/echo hello world",
+		},
+	}
+
+	assert.NoError(t, b.Dispatch(ctx, ur))
+	assert.Equal(t, "hello world", args)
+
+	assert.EqualError(t, b.Dispatch(context.Background(), &UpdateResponse{UpdateID: 5}), "null message found")
+}
+
+func TestDispatchEditedAndChannelPosts(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+
+	var calls []string
+	b.AddCommandHandler("echo", func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		calls = append(calls, "echo "+a+" in "+fmt.Sprint(u.ChatID())+" replying to "+fmt.Sprint(u.EffectiveMessage().ID))
+	})
+	b.SetDefaultHandler(func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		calls = append(calls, "default")
+	})
+	b.SetEditedMessageHandler(func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		calls = append(calls, "edited "+u.EditedMessage.Text)
+	})
+	b.SetChannelPostHandler(func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		calls = append(calls, "channel post "+u.ChannelPost.Text)
+		assert.Zero(t, u.FromID())
+	})
+	b.SetEditedChannelPostHandler(func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		calls = append(calls, "edited channel post "+u.EditedChannelPost.Text)
+	})
+
+	private := &Chat{ID: 4, Type: ChatTypePrivate}
+	channel := &Chat{ID: -100, Type: ChatTypeChannel}
+
+	dispatch := func(ur *UpdateResponse) {
+		assert.NoError(t, b.Dispatch(context.Background(), ur))
+	}
+
+	dispatch(&UpdateResponse{EditedMessage: &Message{ID: 1, From: &User{ID: 3}, Chat: private, Text: "/echo fixed"}})
+	dispatch(&UpdateResponse{ChannelPost: &Message{ID: 2, Chat: channel, Text: "/echo news"}})
+	dispatch(&UpdateResponse{ChannelPost: &Message{ID: 3, Chat: channel, Text: "news"}})
+	dispatch(&UpdateResponse{EditedChannelPost: &Message{ID: 2, Chat: channel, Text: "/echo old news"}})
+
+	// commands in channel posts are dispatched like commands in messages
+	assert.Equal(t, []string{
+		"edited /echo fixed",
+		"echo news in -100 replying to 2",
+		"channel post news",
+		"edited channel post /echo old news",
+	}, calls)
+
+	// edited commands only if enabled
+	calls = nil
+	b.DispatchEditedCommands = true
+
+	dispatch(&UpdateResponse{EditedMessage: &Message{ID: 1, From: &User{ID: 3}, Chat: private, Text: "/echo fixed"}})
+	dispatch(&UpdateResponse{EditedMessage: &Message{ID: 1, From: &User{ID: 3}, Chat: private, Text: "not a command"}})
+	dispatch(&UpdateResponse{EditedChannelPost: &Message{ID: 2, Chat: channel, Text: "/echo old news"}})
+
+	assert.Equal(t, []string{
+		"echo fixed in 4 replying to 1",
+		"edited not a command",
+		"echo old news in -100 replying to 2",
+	}, calls)
+}
+
+func TestCommandInChannelPost(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+
+	var called bool
+	b.AddCommandHandler("start", func(ctx context.Context, b *Bot, u *UpdateResponse, a string) {
+		called = true
+		assert.Nil(t, u.Message)
+		assert.Equal(t, "now", a)
+		assert.Equal(t, int64(5), u.EffectiveMessage().ID)
+		assert.Equal(t, int64(-100), u.ChatID())
+		assert.Zero(t, u.FromID())
+	})
+
+	var ur UpdateResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"update_id": 1,
+		"channel_post": {"message_id": 5, "date": 1, "chat": {"id": -100, "type": "channel", "title": "News"}, "text": "/start@Test_Bot now"}
+	}`), &ur))
+
+	assert.NoError(t, b.Dispatch(context.Background(), &ur))
+	assert.True(t, called)
+}
+
+type testMultipart struct {
+	caption string
+}
+
+func (m *testMultipart) EncodeMultipart(w *multipart.Writer) error {
+	return w.WriteField("caption", m.caption)
+}
+
+func TestCall(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":{"id":1234,"first_name":"Test Bot","username":"Test_Bot","is_bot":true}}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	var me User
+	assert.NoError(t, b.Call(context.Background(), "getMe", nil, &me))
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/getMe", transport.request.URL.String())
+	assert.Equal(t, User{ID: 1234, FirstName: "Test Bot", Username: "Test_Bot", IsBot: true}, me)
+
+	body, _ := ioutil.ReadAll(transport.request.Body)
+	assert.Equal(t, "{}\n", string(body))
+
+	assert.NoError(t, b.Call(context.Background(), "setMyCommands", map[string]interface{}{"commands": []interface{}{}}, nil))
+	assert.Equal(t, "application/json", transport.request.Header.Get("Content-Type"))
+
+	assert.NoError(t, b.Call(context.Background(), "sendPhoto", &testMultipart{"my caption"}, nil))
+	assert.Equal(t, "my caption", transport.request.FormValue("caption"))
+}
+
+func TestCallError(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	err := b.Call(context.Background(), "getChat", map[string]int64{"chat_id": 1}, nil)
+	assert.True(t, IsChatNotFound(err))
+}
+
+func TestGetChatMemberError(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":false,"error_code":400,"description":"Bad Request: user not found"}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	// the result is still returned, so callers checking OK keep working
+	result, err := b.GetChatMember(1, 2)
+	assert.Error(t, err)
+	if assert.NotNil(t, result) {
+		assert.False(t, result.OK)
+		assert.Equal(t, 400, result.ErrorCode)
+	}
+}
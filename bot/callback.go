@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// CallbackQuery represents a press of a callback button in an inline keyboard.
+type CallbackQuery struct {
+	ID              string   `json:"id"`
+	From            *User    `json:"from"`
+	Message         *Message `json:"message,omitempty"` // not set for messages sent in inline mode
+	InlineMessageID string   `json:"inline_message_id,omitempty"`
+	ChatInstance    string   `json:"chat_instance"`
+	Data            string   `json:"data,omitempty"`
+	GameShortName   string   `json:"game_short_name,omitempty"`
+}
+
+// AnswerCallbackQuery represents the payload that needs to be sent to Telegram's
+// answerCallbackQuery method.
+type AnswerCallbackQuery struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+	URL             string `json:"url,omitempty"`
+	CacheTime       int    `json:"cache_time,omitempty"`
+}
+
+// CallbackQueryHandler represents a function that can handle a callback query. data is the
+// query's data without the prefix the handler was registered with.
+type CallbackQueryHandler func(ctx context.Context, b *Bot, ur *UpdateResponse, data string)
+
+// CallbackQueryPatternHandler represents a function that can handle a callback query whose data
+// matches a pattern.
+type CallbackQueryPatternHandler func(ctx context.Context, b *Bot, ur *UpdateResponse, matches []string)
+
+// AddCallbackHandler will register a CallbackQueryHandler for callback data starting with prefix.
+// If several prefixes match, the longest one wins.
+//
+// Example:
+//
+//	b.AddCallbackHandler("vote:", VoteHandler)
+//
+// When a user presses a button with the callback data "vote:yes", VoteHandler is called with "yes".
+func (b *Bot) AddCallbackHandler(prefix string, h CallbackQueryHandler) {
+	b.CallbackHandlers[prefix] = h
+}
+
+// AddCallbackPatternHandler will register a CallbackQueryPatternHandler for callback data that
+// matches r. Pattern handlers are only tried if no prefix matches.
+func (b *Bot) AddCallbackPatternHandler(r *regexp.Regexp, h CallbackQueryPatternHandler) {
+	b.CallbackPatternHandlers[r] = h
+}
+
+// AnswerCallbackQuery will answer a callback query, which stops the progress indicator on the
+// button. Telegram requires an answer to every callback query, so Dispatch sends an empty answer
+// as soon as the handler returns, unless the query was answered while the handler ran.
+func (b *Bot) AnswerCallbackQuery(answer *AnswerCallbackQuery) error {
+	return b.AnswerCallbackQueryContext(context.Background(), answer)
+}
+
+// AnswerCallbackQueryContext is like AnswerCallbackQuery, but with a context.
+func (b *Bot) AnswerCallbackQueryContext(ctx context.Context, answer *AnswerCallbackQuery) error {
+	var result GenericResult
+	if err := b.genericPost(ctx, "answerCallbackQuery", answer, &result); err != nil {
+		return err
+	}
+
+	b.callbacks.answer(answer.CallbackQueryID)
+	return nil
+}
+
+// callbackAnswers records which of the callback queries currently being dispatched were
+// answered. Queries are only tracked while their handler runs.
+type callbackAnswers struct {
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// start begins tracking the query with the given ID.
+func (c *callbackAnswers) start(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pending == nil {
+		c.pending = make(map[string]bool)
+	}
+	c.pending[id] = false
+}
+
+// answer marks the query as answered if it is being tracked.
+func (c *callbackAnswers) answer(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pending[id]; ok {
+		c.pending[id] = true
+	}
+}
+
+// finish stops tracking the query and returns whether it was answered.
+func (c *callbackAnswers) finish(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	answered := c.pending[id]
+	delete(c.pending, id)
+	return answered
+}
+
+// dispatchCallbackQuery calls the handler registered for the query's data. If the query is still
+// unanswered once the handler returned, an empty answer is sent so the client stops waiting.
+func (b *Bot) dispatchCallbackQuery(ctx context.Context, ur *UpdateResponse) error {
+	cq := ur.CallbackQuery
+	b.callbacks.start(cq.ID)
+
+	if b.Debug {
+		log.Printf("callback query received in %s: %s\n", b.BotName, ur.String())
+	}
+
+	if prefix, h := b.callbackHandler(cq.Data); h != nil {
+		h(ctx, b, ur, strings.TrimPrefix(cq.Data, prefix))
+	} else {
+		for r, h := range b.CallbackPatternHandlers {
+			if matches := r.FindStringSubmatch(cq.Data); matches != nil {
+				h(ctx, b, ur, matches)
+			}
+		}
+	}
+
+	if b.callbacks.finish(cq.ID) {
+		return nil
+	}
+
+	if err := b.AnswerCallbackQueryContext(ctx, &AnswerCallbackQuery{CallbackQueryID: cq.ID}); err != nil {
+		log.Printf("error: could not answer callback query %s: %s\n", cq.ID, err)
+	}
+
+	return nil
+}
+
+// callbackHandler returns the handler with the longest prefix of data.
+func (b *Bot) callbackHandler(data string) (string, CallbackQueryHandler) {
+	var prefix string
+	var handler CallbackQueryHandler
+
+	for p, h := range b.CallbackHandlers {
+		if strings.HasPrefix(data, p) && (handler == nil || len(p) > len(prefix)) {
+			prefix, handler = p, h
+		}
+	}
+
+	return prefix, handler
+}
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func callbackUpdate(id, data string) *UpdateResponse {
+	return &UpdateResponse{
+		CallbackQuery: &CallbackQuery{
+			ID:   id,
+			From: &User{ID: 1, FirstName: "John"},
+			Message: &Message{
+				ID:   10,
+				Chat: &Chat{ID: 2, Type: ChatTypePrivate},
+			},
+			ChatInstance: "42",
+			Data:         data,
+		},
+	}
+}
+
+func TestCallbackHandlers(t *testing.T) {
+	var answers []AnswerCallbackQuery
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botmysecrettoken/answerCallbackQuery", r.URL.Path)
+
+		var answer AnswerCallbackQuery
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&answer))
+		answers = append(answers, answer)
+
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	})
+
+	var calls []string
+	b.AddCallbackHandler("vote:", func(ctx context.Context, b *Bot, ur *UpdateResponse, data string) {
+		calls = append(calls, "vote "+data)
+		assert.Equal(t, int64(2), ur.ChatID())
+		assert.Equal(t, int64(1), ur.FromID())
+
+		assert.NoError(t, b.AnswerCallbackQueryContext(ctx, &AnswerCallbackQuery{
+			CallbackQueryID: ur.CallbackQuery.ID,
+			Text:            "Thanks for voting",
+		}))
+	})
+	b.AddCallbackHandler("vote:admin:", func(ctx context.Context, b *Bot, ur *UpdateResponse, data string) {
+		calls = append(calls, "admin "+data)
+	})
+	b.AddCallbackPatternHandler(regexp.MustCompile(`^page:(\d+)$`), func(ctx context.Context, b *Bot, ur *UpdateResponse, matches []string) {
+		calls = append(calls, "page "+matches[1])
+	})
+
+	// the handler answers the query itself
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q1", "vote:yes")))
+	assert.Equal(t, []AnswerCallbackQuery{{CallbackQueryID: "q1", Text: "Thanks for voting"}}, answers)
+
+	// the longest prefix wins, and queries the handler doesn't answer get an empty answer
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q2", "vote:admin:close")))
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q3", "page:3")))
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q4", "unknown")))
+
+	assert.Equal(t, []string{"vote yes", "admin close", "page 3"}, calls)
+	assert.Equal(t, []AnswerCallbackQuery{
+		{CallbackQueryID: "q1", Text: "Thanks for voting"},
+		{CallbackQueryID: "q2"},
+		{CallbackQueryID: "q3"},
+		{CallbackQueryID: "q4"},
+	}, answers)
+}
+
+func TestCallbackAutoAnswer(t *testing.T) {
+	var answers []string
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		var answer AnswerCallbackQuery
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&answer))
+		answers = append(answers, answer.CallbackQueryID+":"+answer.Text)
+
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	})
+
+	b.AddCallbackHandler("other:", func(ctx context.Context, b *Bot, ur *UpdateResponse, data string) {
+		// answering another query doesn't count as answering this one
+		assert.NoError(t, b.AnswerCallbackQueryContext(ctx, &AnswerCallbackQuery{CallbackQueryID: data, Text: "other"}))
+	})
+
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q1", "other:q0")))
+	assert.Equal(t, []string{"q0:other", "q1:"}, answers)
+
+	// answers without the handler's context count as well
+	b.AddCallbackHandler("plain:", func(ctx context.Context, b *Bot, ur *UpdateResponse, data string) {
+		assert.NoError(t, b.AnswerCallbackQuery(&AnswerCallbackQuery{CallbackQueryID: ur.CallbackQuery.ID, Text: "plain"}))
+	})
+	b.AddCallbackHandler("async:", func(ctx context.Context, b *Bot, ur *UpdateResponse, data string) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, b.AnswerCallbackQuery(&AnswerCallbackQuery{CallbackQueryID: ur.CallbackQuery.ID, Text: "async"}))
+		}()
+		<-done
+	})
+
+	answers = nil
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q3", "plain:")))
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q4", "async:")))
+	assert.Equal(t, []string{"q3:plain", "q4:async"}, answers)
+
+	// answers outside of Dispatch leave nothing behind which would suppress a later auto answer
+	answers = nil
+	assert.NoError(t, b.AnswerCallbackQuery(&AnswerCallbackQuery{CallbackQueryID: "q2", Text: "early"}))
+	assert.NoError(t, b.Dispatch(context.Background(), callbackUpdate("q2", "unknown")))
+	assert.Equal(t, []string{"q2:early", "q2:"}, answers)
+}
+
+func TestCallbackQueryFromInlineMessage(t *testing.T) {
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	})
+
+	called := false
+	b.AddCallbackHandler("vote:", func(ctx context.Context, b *Bot, ur *UpdateResponse, data string) {
+		called = true
+		assert.Nil(t, ur.EffectiveMessage())
+		assert.Nil(t, ur.EffectiveChat())
+		assert.Equal(t, int64(0), ur.ChatID())
+		assert.Equal(t, int64(1), ur.FromID())
+		assert.False(t, ur.IsGroup())
+		assert.False(t, ur.IsPrivate())
+		assert.False(t, ur.IsBotReply(b))
+	})
+
+	ur := callbackUpdate("q1", "vote:yes")
+	ur.CallbackQuery.Message = nil
+	ur.CallbackQuery.InlineMessageID = "inline-1"
+
+	assert.NoError(t, b.Dispatch(context.Background(), ur))
+	assert.True(t, called)
+}
+
+func TestInlineKeyboard(t *testing.T) {
+	markup := NewInlineKeyboard(
+		[]InlineKeyboardButton{NewCallbackButton("Yes", "vote:yes"), NewURLButton("Results", "https://example.com")},
+		[]InlineKeyboardButton{NewSwitchInlineQueryButton("Share", ""), NewWebAppButton("App", "https://example.com/app")},
+		[]InlineKeyboardButton{NewLoginURLButton("Log in", "https://example.com/login")},
+	)
+
+	b, err := json.Marshal(markup)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"inline_keyboard":[
+		[{"text":"Yes","callback_data":"vote:yes"},{"text":"Results","url":"https://example.com"}],
+		[{"text":"Share","switch_inline_query":""},{"text":"App","web_app":{"url":"https://example.com/app"}}],
+		[{"text":"Log in","login_url":{"url":"https://example.com/login"}}]
+	]}`, string(b))
+}
+
+func TestDecodeCallbackQuery(t *testing.T) {
+	jsonStr := `{"update_id":1,"callback_query":{"id":"4382","from":{"id":1,"first_name":"John"},"message":{"message_id":10,"date":1449369855,"chat":{"id":2,"type":"private"},"text":"Vote!","reply_markup":{"inline_keyboard":[[{"text":"Yes","callback_data":"vote:yes"}]]}},"chat_instance":"42","data":"vote:yes"}}`
+
+	var ur UpdateResponse
+	assert.NoError(t, json.Unmarshal([]byte(jsonStr), &ur))
+	assert.Equal(t, "vote:yes", ur.CallbackQuery.Data)
+	assert.Equal(t, "vote:yes", ur.CallbackQuery.Message.ReplyMarkup.InlineKeyboard[0][0].CallbackData)
+}
@@ -1,7 +1,8 @@
 package bot
 
 import (
-	"encoding/json"
+	"context"
+	"net/http"
 	"net/url"
 	"strconv"
 )
@@ -20,6 +21,7 @@ const (
 type ChatMember struct {
 	User                  *User  `json:"user"`
 	Status                Status `json:"status"`
+	IsMember              bool   `json:"is_member,omitempty"` // only for restricted members
 	UntilDate             int    `json:"until_date,omit_empty"`
 	CanBeEdited           bool   `json:"can_be_edited,omit_empty"`
 	CanChangeInfo         bool   `json:"can_change_info,omit_empty"`
@@ -40,23 +42,32 @@ func (c *ChatMember) IsValidStatus() bool {
 	return c.Status != StatusKicked && c.Status != StatusLeft
 }
 
+// GetChatMember will return information about a member of a chat. If Telegram answered with an
+// error, the result is returned along with it, with OK set to false.
 func (b *Bot) GetChatMember(chatID, userID int64) (*ChatMemberResult, error) {
+	return b.GetChatMemberContext(context.Background(), chatID, userID)
+}
+
+// GetChatMemberContext is like GetChatMember, but with a context.
+func (b *Bot) GetChatMemberContext(ctx context.Context, chatID, userID int64) (*ChatMemberResult, error) {
 	v := url.Values{}
 	v.Set("chat_id", strconv.FormatInt(chatID, 10))
 	v.Set("user_id", strconv.FormatInt(userID, 10))
 	url := b.URL("getChatMember") + "?" + v.Encode()
 
-	resp, err := b.client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	dec := json.NewDecoder(resp.Body)
 	var result ChatMemberResult
-	if err := dec.Decode(&result); err != nil {
+	err := b.do(ctx, "getChatMember", func() (*http.Request, error) {
+		r, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return r, nil
+	}, &result)
+	if err != nil && result.ErrorCode == 0 {
+		// no response was decoded from Telegram
 		return nil, err
 	}
 
-	return &result, nil
+	return &result, err
 }
@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"context"
+	"log"
+)
+
+// MemberTransition describes how a chat member's status changed in a ChatMemberUpdated.
+type MemberTransition string
+
+// Transitions returned by ChatMemberUpdated.Transition.
+const (
+	TransitionJoined       MemberTransition = "joined"       // left or banned, now in the chat
+	TransitionLeft         MemberTransition = "left"         // left the chat or was removed
+	TransitionPromoted     MemberTransition = "promoted"     // became an administrator
+	TransitionDemoted      MemberTransition = "demoted"      // is no longer an administrator
+	TransitionRestricted   MemberTransition = "restricted"   // may no longer do everything members can
+	TransitionUnrestricted MemberTransition = "unrestricted" // restrictions were lifted
+	TransitionBanned       MemberTransition = "banned"       // was kicked and can't join again
+	TransitionUnbanned     MemberTransition = "unbanned"     // may join again, but isn't in the chat
+	TransitionChanged      MemberTransition = "changed"      // e.g. different administrator rights
+)
+
+// ChatMemberUpdated represents a change of a chat member's status. Telegram only sends chat_member
+// updates if they are listed in AllowedUpdates and the bot is an administrator of the chat.
+type ChatMemberUpdated struct {
+	Chat                    *Chat           `json:"chat"`
+	From                    *User           `json:"from"` // who made the change
+	Date                    int             `json:"date"`
+	OldChatMember           *ChatMember     `json:"old_chat_member"`
+	NewChatMember           *ChatMember     `json:"new_chat_member"`
+	InviteLink              *ChatInviteLink `json:"invite_link,omitempty"`
+	ViaJoinRequest          bool            `json:"via_join_request,omitempty"`
+	ViaChatFolderInviteLink bool            `json:"via_chat_folder_invite_link,omitempty"`
+}
+
+// ChatJoinRequest represents a request to join a chat. It must be answered with
+// PostApproveChatJoinRequest or PostDeclineChatJoinRequest.
+type ChatJoinRequest struct {
+	Chat       *Chat           `json:"chat"`
+	From       *User           `json:"from"`
+	UserChatID int64           `json:"user_chat_id"` // private chat with the user, usable for 5 minutes
+	Date       int             `json:"date"`
+	Bio        string          `json:"bio,omitempty"`
+	InviteLink *ChatInviteLink `json:"invite_link,omitempty"`
+}
+
+// ChatInviteLink represents an invite link for a chat.
+type ChatInviteLink struct {
+	InviteLink              string `json:"invite_link"`
+	Creator                 *User  `json:"creator"`
+	CreatesJoinRequest      bool   `json:"creates_join_request"`
+	IsPrimary               bool   `json:"is_primary"`
+	IsRevoked               bool   `json:"is_revoked"`
+	Name                    string `json:"name,omitempty"`
+	ExpireDate              int    `json:"expire_date,omitempty"`
+	MemberLimit             int    `json:"member_limit,omitempty"`
+	PendingJoinRequestCount int    `json:"pending_join_request_count,omitempty"`
+}
+
+// ApproveChatJoinRequest represents the payload that needs to be sent to Telegram's
+// approveChatJoinRequest method.
+type ApproveChatJoinRequest struct {
+	ChatID int64 `json:"chat_id"`
+	UserID int64 `json:"user_id"`
+}
+
+// DeclineChatJoinRequest represents the payload that needs to be sent to Telegram's
+// declineChatJoinRequest method.
+type DeclineChatJoinRequest struct {
+	ChatID int64 `json:"chat_id"`
+	UserID int64 `json:"user_id"`
+}
+
+// Transition classifies the change by comparing OldChatMember with NewChatMember.
+func (u *ChatMemberUpdated) Transition() MemberTransition {
+	before, after := u.OldChatMember, u.NewChatMember
+
+	switch {
+	case after.Status == StatusKicked && before.Status != StatusKicked:
+		return TransitionBanned
+	case !before.IsInChat() && after.IsInChat():
+		return TransitionJoined
+	case before.IsInChat() && !after.IsInChat():
+		return TransitionLeft
+	case !before.IsInChat():
+		if before.Status == StatusKicked {
+			return TransitionUnbanned
+		}
+		if after.Status == StatusRestricted && before.Status != StatusRestricted {
+			return TransitionRestricted
+		}
+	case after.IsAdmin() && !before.IsAdmin():
+		return TransitionPromoted
+	case before.IsAdmin() && !after.IsAdmin():
+		return TransitionDemoted
+	case after.Status == StatusRestricted && before.Status != StatusRestricted:
+		return TransitionRestricted
+	case before.Status == StatusRestricted && after.Status != StatusRestricted:
+		return TransitionUnrestricted
+	}
+
+	return TransitionChanged
+}
+
+// IsAdmin returns true if the member is the creator or an administrator of the chat.
+func (c *ChatMember) IsAdmin() bool {
+	return c.Status == StatusCreator || c.Status == StatusAdministrator
+}
+
+// IsInChat returns true if the user is currently a member of the chat, including restricted
+// members who haven't left.
+func (c *ChatMember) IsInChat() bool {
+	if c.Status == StatusRestricted {
+		return c.IsMember
+	}
+
+	return c.IsValidStatus()
+}
+
+// SetMyChatMemberHandler will register a handler to be called when the bot's own status in a chat
+// changed, e.g. it was added to a group or promoted. args is the MemberTransition.
+func (b *Bot) SetMyChatMemberHandler(h Handler) {
+	b.MyChatMemberHandler = h
+}
+
+// SetChatMemberHandler will register a handler to be called when the status of a user in a chat
+// changed, e.g. they joined or were banned. args is the MemberTransition.
+func (b *Bot) SetChatMemberHandler(h Handler) {
+	b.ChatMemberHandler = h
+}
+
+// SetChatJoinRequestHandler will register a handler to be called when a user asks to join a chat.
+// args is empty.
+func (b *Bot) SetChatJoinRequestHandler(h Handler) {
+	b.ChatJoinRequestHandler = h
+}
+
+// PostApproveChatJoinRequest will let the user of a join request into the chat.
+func (b *Bot) PostApproveChatJoinRequest(req *ApproveChatJoinRequest) error {
+	return b.PostApproveChatJoinRequestContext(context.Background(), req)
+}
+
+// PostApproveChatJoinRequestContext is like PostApproveChatJoinRequest, but with a context.
+func (b *Bot) PostApproveChatJoinRequestContext(ctx context.Context, req *ApproveChatJoinRequest) error {
+	var result GenericResult
+	return b.genericPost(ctx, "approveChatJoinRequest", req, &result)
+}
+
+// PostDeclineChatJoinRequest will turn down a join request.
+func (b *Bot) PostDeclineChatJoinRequest(req *DeclineChatJoinRequest) error {
+	return b.PostDeclineChatJoinRequestContext(context.Background(), req)
+}
+
+// PostDeclineChatJoinRequestContext is like PostDeclineChatJoinRequest, but with a context.
+func (b *Bot) PostDeclineChatJoinRequestContext(ctx context.Context, req *DeclineChatJoinRequest) error {
+	var result GenericResult
+	return b.genericPost(ctx, "declineChatJoinRequest", req, &result)
+}
+
+// dispatchChatMember passes chat member updates and join requests to their handlers.
+func (b *Bot) dispatchChatMember(ctx context.Context, ur *UpdateResponse) error {
+	if b.Debug {
+		log.Printf("received in %s: %s\n", b.BotName, ur.String())
+	}
+
+	switch {
+	case ur.MyChatMember != nil:
+		if h := b.MyChatMemberHandler; h != nil {
+			h(ctx, b, ur, string(ur.MyChatMember.Transition()))
+		}
+	case ur.ChatMember != nil:
+		if h := b.ChatMemberHandler; h != nil {
+			h(ctx, b, ur, string(ur.ChatMember.Transition()))
+		}
+	case ur.ChatJoinRequest != nil:
+		if h := b.ChatJoinRequestHandler; h != nil {
+			h(ctx, b, ur, "")
+		}
+	}
+
+	return nil
+}
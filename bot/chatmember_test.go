@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatMemberUpdatedTransition(t *testing.T) {
+	member := &ChatMember{Status: StatusMember}
+	admin := &ChatMember{Status: StatusAdministrator}
+	creator := &ChatMember{Status: StatusCreator}
+	left := &ChatMember{Status: StatusLeft}
+	kicked := &ChatMember{Status: StatusKicked}
+	restricted := &ChatMember{Status: StatusRestricted, IsMember: true}
+	restrictedLeft := &ChatMember{Status: StatusRestricted}
+
+	tests := []struct {
+		old, new *ChatMember
+		want     MemberTransition
+	}{
+		{left, member, TransitionJoined},
+		{kicked, member, TransitionJoined},
+		{left, admin, TransitionJoined},
+		{left, restricted, TransitionJoined},
+		{member, left, TransitionLeft},
+		{restricted, restrictedLeft, TransitionLeft},
+		{member, kicked, TransitionBanned},
+		{restricted, kicked, TransitionBanned},
+		{left, kicked, TransitionBanned},
+		{kicked, left, TransitionUnbanned},
+		{left, restrictedLeft, TransitionRestricted},
+		{member, admin, TransitionPromoted},
+		{member, creator, TransitionPromoted},
+		{admin, member, TransitionDemoted},
+		{member, restricted, TransitionRestricted},
+		{restricted, member, TransitionUnrestricted},
+		{admin, admin, TransitionChanged},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s to %s", test.old.Status, test.new.Status), func(t *testing.T) {
+			u := &ChatMemberUpdated{OldChatMember: test.old, NewChatMember: test.new}
+			assert.Equal(t, test.want, u.Transition())
+		})
+	}
+}
+
+func TestDispatchChatMember(t *testing.T) {
+	var approved ApproveChatJoinRequest
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botmysecrettoken/approveChatJoinRequest", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&approved))
+
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	})
+
+	var calls []string
+	b.SetMyChatMemberHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		calls = append(calls, fmt.Sprintf("bot %s in %d by %d", args, ur.ChatID(), ur.FromID()))
+	})
+	b.SetChatMemberHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		calls = append(calls, fmt.Sprintf("%s %s", ur.ChatMember.NewChatMember.User.FirstName, args))
+	})
+	b.SetChatJoinRequestHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		calls = append(calls, "request from "+ur.ChatJoinRequest.From.FirstName)
+		assert.True(t, ur.IsGroup())
+
+		assert.NoError(t, b.PostApproveChatJoinRequestContext(ctx, &ApproveChatJoinRequest{
+			ChatID: ur.ChatID(),
+			UserID: ur.FromID(),
+		}))
+	})
+
+	const updates = `[
+		{"update_id": 1, "my_chat_member": {
+			"chat": {"id": -100, "type": "supergroup"},
+			"from": {"id": 1, "first_name": "John"},
+			"date": 1700000000,
+			"old_chat_member": {"user": {"id": 99, "first_name": "Test_Bot", "is_bot": true}, "status": "left"},
+			"new_chat_member": {"user": {"id": 99, "first_name": "Test_Bot", "is_bot": true}, "status": "member"}
+		}},
+		{"update_id": 2, "chat_member": {
+			"chat": {"id": -100, "type": "supergroup"},
+			"from": {"id": 1, "first_name": "John"},
+			"date": 1700000001,
+			"old_chat_member": {"user": {"id": 2, "first_name": "Jane"}, "status": "member"},
+			"new_chat_member": {"user": {"id": 2, "first_name": "Jane"}, "status": "restricted", "is_member": true}
+		}},
+		{"update_id": 3, "chat_join_request": {
+			"chat": {"id": -100, "type": "supergroup"},
+			"from": {"id": 3, "first_name": "Jim"},
+			"user_chat_id": 3,
+			"date": 1700000002,
+			"bio": "hi"
+		}}
+	]`
+
+	var urs []UpdateResponse
+	assert.NoError(t, json.Unmarshal([]byte(updates), &urs))
+
+	for i := range urs {
+		assert.NoError(t, b.Dispatch(context.Background(), &urs[i]))
+	}
+
+	assert.Equal(t, []string{
+		"bot joined in -100 by 1",
+		"Jane restricted",
+		"request from Jim",
+	}, calls)
+	assert.Equal(t, ApproveChatJoinRequest{ChatID: -100, UserID: 3}, approved)
+}
@@ -0,0 +1,166 @@
+package bot
+
+// Types of MessageEntity.
+const (
+	EntityMention       = "mention"      // @username
+	EntityHashtag       = "hashtag"      // #hashtag
+	EntityCashtag       = "cashtag"      // $USD
+	EntityBotCommand    = "bot_command"  // /start@jobs_bot
+	EntityURL           = "url"          // https://telegram.org
+	EntityEmail         = "email"        // do-not-reply@telegram.org
+	EntityPhoneNumber   = "phone_number" // +1-212-555-0123
+	EntityBold          = "bold"
+	EntityItalic        = "italic"
+	EntityUnderline     = "underline"
+	EntityStrikethrough = "strikethrough"
+	EntitySpoiler       = "spoiler"
+	EntityBlockquote    = "blockquote"
+	EntityCode          = "code"
+	EntityPre           = "pre"
+	EntityTextLink      = "text_link"    // clickable text with a URL
+	EntityTextMention   = "text_mention" // mention of a user without a username
+	EntityCustomEmoji   = "custom_emoji"
+)
+
+// MessageEntity represents one special entity in a text message, e.g. a hashtag, a URL or bold
+// text. Offset and Length are measured in UTF-16 code units.
+type MessageEntity struct {
+	Type          string `json:"type"`
+	Offset        int    `json:"offset"`
+	Length        int    `json:"length"`
+	URL           string `json:"url,omitempty"`
+	User          *User  `json:"user,omitempty"`
+	Language      string `json:"language,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+}
+
+// Text returns the part of s the entity covers. s is the text or caption the entity belongs to.
+func (e MessageEntity) Text(s string) string {
+	start := ByteOffset(s, e.Offset)
+	end := start + ByteOffset(s[start:], e.Length)
+
+	return s[start:end]
+}
+
+// UTF16Len returns the length of s in UTF-16 code units, the unit Telegram measures text in.
+func UTF16Len(s string) int {
+	return UTF16Offset(s, len(s))
+}
+
+// UTF16Offset converts a byte offset in s into UTF-16 code units, e.g. to build a MessageEntity
+// for text found with the strings package.
+func UTF16Offset(s string, byteOffset int) int {
+	if byteOffset > len(s) {
+		byteOffset = len(s)
+	}
+
+	units := 0
+	for _, r := range s[:byteOffset] {
+		units += utf16RuneLen(r)
+	}
+
+	return units
+}
+
+// ByteOffset converts an offset in UTF-16 code units into a byte offset in s. Offsets past the
+// end of s return len(s).
+func ByteOffset(s string, utf16Offset int) int {
+	units := 0
+	for i, r := range s {
+		if units >= utf16Offset {
+			return i
+		}
+
+		units += utf16RuneLen(r)
+	}
+
+	return len(s)
+}
+
+// utf16RuneLen returns 2 for runes encoded as a surrogate pair, such as most emoji, and 1 for
+// anything else.
+func utf16RuneLen(r rune) int {
+	if r > 0xFFFF {
+		return 2
+	}
+
+	return 1
+}
+
+// EntityText returns the part of the message's text or caption an entity covers.
+func (m *Message) EntityText(e MessageEntity) string {
+	for _, te := range m.Entities {
+		if te == e {
+			return e.Text(m.Text)
+		}
+	}
+
+	return e.Text(m.Caption)
+}
+
+// Mentions returns every @username mentioned in the message's text or caption.
+func (m *Message) Mentions() []string {
+	return m.entityTexts(EntityMention)
+}
+
+// Hashtags returns every #hashtag in the message's text or caption.
+func (m *Message) Hashtags() []string {
+	return m.entityTexts(EntityHashtag)
+}
+
+// BotCommands returns every /command in the message's text or caption, including the bot name
+// if there is one.
+func (m *Message) BotCommands() []string {
+	return m.entityTexts(EntityBotCommand)
+}
+
+// URLs returns every URL in the message's text or caption: URLs written out in the text as well
+// as the targets of text links.
+func (m *Message) URLs() []string {
+	var urls []string
+	m.eachEntity(func(s string, e MessageEntity) {
+		switch e.Type {
+		case EntityURL:
+			urls = append(urls, e.Text(s))
+		case EntityTextLink:
+			urls = append(urls, e.URL)
+		}
+	})
+
+	return urls
+}
+
+// TextMentions returns the users mentioned in the message who have no username.
+func (m *Message) TextMentions() []*User {
+	var users []*User
+	m.eachEntity(func(s string, e MessageEntity) {
+		if e.Type == EntityTextMention && e.User != nil {
+			users = append(users, e.User)
+		}
+	})
+
+	return users
+}
+
+func (m *Message) entityTexts(entityType string) []string {
+	var texts []string
+	m.eachEntity(func(s string, e MessageEntity) {
+		if e.Type == entityType {
+			texts = append(texts, e.Text(s))
+		}
+	})
+
+	return texts
+}
+
+// eachEntity calls fn for every entity of the text and then the caption, along with the string
+// the entity belongs to.
+func (m *Message) eachEntity(fn func(s string, e MessageEntity)) {
+	for _, e := range m.Entities {
+		fn(m.Text, e)
+	}
+
+	for _, e := range m.CaptionEntities {
+		fn(m.Caption, e)
+	}
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUTF16Offsets(t *testing.T) {
+	// 👍 is outside the Basic Multilingual Plane, so it's a surrogate pair in UTF-16
+	s := "a👍é b"
+
+	assert.Equal(t, 6, UTF16Len(s))
+	assert.Equal(t, 0, ByteOffset(s, 0))
+	assert.Equal(t, 1, ByteOffset(s, 1))
+	assert.Equal(t, 5, ByteOffset(s, 3), "after the surrogate pair")
+	assert.Equal(t, 7, ByteOffset(s, 4))
+	assert.Equal(t, len(s), ByteOffset(s, 100))
+
+	assert.Equal(t, 3, UTF16Offset(s, 5))
+	assert.Equal(t, 5, UTF16Offset(s, strings.Index(s, "b")))
+	assert.Equal(t, 6, UTF16Offset(s, 100))
+}
+
+func TestEntityText(t *testing.T) {
+	s := "🎉🎉 Hi @john, see https://example.com/ü #go 👍"
+
+	tests := []struct {
+		entity MessageEntity
+		text   string
+	}{
+		{MessageEntity{Type: EntityMention, Offset: 8, Length: 5}, "@john"},
+		{MessageEntity{Type: EntityURL, Offset: 19, Length: 21}, "https://example.com/ü"},
+		{MessageEntity{Type: EntityHashtag, Offset: 41, Length: 3}, "#go"},
+		{MessageEntity{Type: EntityBold, Offset: 0, Length: 4}, "🎉🎉"},
+		{MessageEntity{Type: EntityItalic, Offset: 45, Length: 2}, "👍"},
+		{MessageEntity{Type: EntityItalic, Offset: 45, Length: 10}, "👍"},
+		{MessageEntity{Type: EntityItalic, Offset: 60, Length: 2}, ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.text, test.entity.Text(s))
+	}
+}
+
+func TestMessageEntities(t *testing.T) {
+	john := &User{ID: 5, FirstName: "John"}
+
+	m := &Message{
+		Text: "👋 /start@Test_Bot @jane #news see https://t.me and docs",
+		Entities: []MessageEntity{
+			{Type: EntityBotCommand, Offset: 3, Length: 15},
+			{Type: EntityMention, Offset: 19, Length: 5},
+			{Type: EntityHashtag, Offset: 25, Length: 5},
+			{Type: EntityURL, Offset: 35, Length: 12},
+			{Type: EntityTextLink, Offset: 52, Length: 4, URL: "https://core.telegram.org"},
+		},
+		Caption: "🖼 by John #photo",
+		CaptionEntities: []MessageEntity{
+			{Type: EntityTextMention, Offset: 6, Length: 4, User: john},
+			{Type: EntityHashtag, Offset: 11, Length: 6},
+		},
+	}
+
+	assert.Equal(t, []string{"/start@Test_Bot"}, m.BotCommands())
+	assert.Equal(t, []string{"@jane"}, m.Mentions())
+	assert.Equal(t, []string{"#news", "#photo"}, m.Hashtags())
+	assert.Equal(t, []string{"https://t.me", "https://core.telegram.org"}, m.URLs())
+	assert.Equal(t, []*User{john}, m.TextMentions())
+
+	assert.Equal(t, "docs", m.EntityText(m.Entities[4]))
+	assert.Equal(t, "John", m.EntityText(m.CaptionEntities[0]))
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors which an *APIError matches with errors.Is.
+var (
+	ErrFloodWait     = errors.New("bot: too many requests")
+	ErrBlockedByUser = errors.New("bot: bot was blocked by the user")
+	ErrChatNotFound  = errors.New("bot: chat not found")
+	ErrChatMigrated  = errors.New("bot: group chat was upgraded to a supergroup chat")
+	ErrUnauthorized  = errors.New("bot: unauthorized")
+	ErrInvalidFileID = errors.New("bot: wrong file identifier")
+)
+
+// APIError represents an unsuccessful response from the Telegram Bot API.
+type APIError struct {
+	Method      string
+	ErrorCode   int
+	Description string
+
+	// RetryAfter is how long to wait before repeating the request after a flood wait.
+	RetryAfter time.Duration
+
+	// MigrateToChatID is the new ID of a group which was upgraded to a supergroup.
+	MigrateToChatID int64
+}
+
+func newAPIError(method string, gr *GenericResult) *APIError {
+	e := &APIError{
+		Method:      method,
+		ErrorCode:   gr.ErrorCode,
+		Description: gr.Description,
+	}
+
+	if p := gr.Parameters; p != nil {
+		e.RetryAfter = time.Duration(p.RetryAfter) * time.Second
+		e.MigrateToChatID = p.MigrateToChatID
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bot: failed request to %s { %d, %s }", e.Method, e.ErrorCode, e.Description)
+}
+
+// Is reports whether the error matches one of the sentinel errors, e.g. ErrFloodWait.
+func (e *APIError) Is(target error) bool {
+	description := strings.ToLower(e.Description)
+
+	switch target {
+	case ErrFloodWait:
+		return e.ErrorCode == http.StatusTooManyRequests
+	case ErrBlockedByUser:
+		return e.ErrorCode == http.StatusForbidden && strings.Contains(description, "bot was blocked by the user")
+	case ErrChatNotFound:
+		return e.ErrorCode == http.StatusBadRequest && strings.Contains(description, "chat not found")
+	case ErrChatMigrated:
+		return e.MigrateToChatID != 0
+	case ErrUnauthorized:
+		return e.ErrorCode == http.StatusUnauthorized
+	case ErrInvalidFileID:
+		return e.ErrorCode == http.StatusBadRequest &&
+			(strings.Contains(description, "file identifier") || strings.Contains(description, "file_id"))
+	}
+
+	return false
+}
+
+// IsFloodWait returns true if Telegram rejected the request because of flood control. The
+// *APIError's RetryAfter says how long to wait.
+func IsFloodWait(err error) bool {
+	return errors.Is(err, ErrFloodWait)
+}
+
+// IsBlockedByUser returns true if the user blocked the bot.
+func IsBlockedByUser(err error) bool {
+	return errors.Is(err, ErrBlockedByUser)
+}
+
+// IsChatNotFound returns true if the chat doesn't exist or the bot can't see it.
+func IsChatNotFound(err error) bool {
+	return errors.Is(err, ErrChatNotFound)
+}
+
+// IsChatMigrated returns true if the group was upgraded to a supergroup. The *APIError's
+// MigrateToChatID holds the new chat ID.
+func IsChatMigrated(err error) bool {
+	return errors.Is(err, ErrChatMigrated)
+}
+
+// IsInvalidFileID returns true if Telegram doesn't accept a file_id, e.g. because it belongs to
+// another bot or the file was deleted.
+func IsInvalidFileID(err error) bool {
+	return errors.Is(err, ErrInvalidFileID)
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`)
+
+	b := New("Test_Bot", "mysecrettoken")
+	b.client = &http.Client{Transport: transport}
+
+	result, err := b.PostSendMessage(&SendMessage{ChatID: 1000, Text: "hello"})
+	assert.NotNil(t, result)
+	assert.EqualError(t, err, "bot: failed request to sendMessage { 429, Too Many Requests: retry after 5 }")
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "sendMessage", apiErr.Method)
+	assert.Equal(t, 429, apiErr.ErrorCode)
+	assert.Equal(t, 5*time.Second, apiErr.RetryAfter)
+
+	assert.True(t, IsFloodWait(err))
+	assert.True(t, IsFloodWait(fmt.Errorf("wrapped: %w", err)))
+	assert.False(t, IsBlockedByUser(err))
+	assert.False(t, IsChatNotFound(err))
+	assert.False(t, IsChatMigrated(err))
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	blocked := &APIError{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"}
+	assert.True(t, IsBlockedByUser(blocked))
+	assert.False(t, IsFloodWait(blocked))
+
+	notFound := &APIError{ErrorCode: 400, Description: "Bad Request: chat not found"}
+	assert.True(t, IsChatNotFound(notFound))
+	assert.False(t, IsBlockedByUser(notFound))
+
+	migrated := newAPIError("sendMessage", &GenericResult{
+		ErrorCode:   400,
+		Description: "Bad Request: group chat was upgraded to a supergroup chat",
+		Parameters:  &ResponseParameters{MigrateToChatID: -1001234},
+	})
+	assert.True(t, IsChatMigrated(migrated))
+	assert.Equal(t, int64(-1001234), migrated.MigrateToChatID)
+
+	wrongFileID := &APIError{ErrorCode: 400, Description: "Bad Request: wrong file identifier/HTTP URL specified"}
+	assert.True(t, IsInvalidFileID(wrongFileID))
+	assert.False(t, IsInvalidFileID(notFound))
+
+	assert.True(t, errors.Is(&APIError{ErrorCode: 401}, ErrUnauthorized))
+	assert.False(t, IsFloodWait(errors.New("some other error")))
+}
+
+func TestAPIErrorNonJSON(t *testing.T) {
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<html>Bad Gateway</html>", http.StatusBadGateway)
+	})
+
+	_, err := b.PostSendMessage(&SendMessage{ChatID: 1000, Text: "hello"})
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.ErrorCode)
+}
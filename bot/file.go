@@ -0,0 +1,225 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// ErrFileTooLarge is returned by DownloadFile when a file exceeds the size set with WithMaxSize.
+var ErrFileTooLarge = errors.New("bot: file exceeds the maximum download size")
+
+// File represents a file ready to be downloaded, as returned by getFile.
+type File struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FilePath     string `json:"file_path,omitempty"`
+}
+
+// GetFile represents the payload that needs to be sent to Telegram's getFile method.
+type GetFile struct {
+	FileID string `json:"file_id"`
+}
+
+// FileResult represents the result of a getFile call.
+type FileResult struct {
+	GenericResult
+	Result *File `json:"result"`
+}
+
+// DownloadOption configures a call to DownloadFile.
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	offset  int64
+	maxSize int64
+}
+
+// WithOffset starts the download at offset bytes into the file, e.g. to resume a download that
+// was interrupted after offset bytes were written.
+func WithOffset(offset int64) DownloadOption {
+	return func(o *downloadOptions) {
+		o.offset = offset
+	}
+}
+
+// WithMaxSize makes DownloadFile fail with ErrFileTooLarge if the file is larger than maxSize bytes.
+func WithMaxSize(maxSize int64) DownloadOption {
+	return func(o *downloadOptions) {
+		o.maxSize = maxSize
+	}
+}
+
+// GetFile will return the information needed to download a file from Telegram's getFile method.
+func (b *Bot) GetFile(fileID string) (*File, error) {
+	return b.GetFileContext(context.Background(), fileID)
+}
+
+// GetFileContext is like GetFile, but with a context.
+func (b *Bot) GetFileContext(ctx context.Context, fileID string) (*File, error) {
+	var result FileResult
+	if err := b.genericPost(ctx, "getFile", &GetFile{fileID}, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
+
+// DownloadFile writes the content of a file to w and returns the number of bytes written.
+//
+// If the connection breaks during the download, it is resumed where it stopped according to the
+// RetryPolicy. The file is read straight from disk when using WithLocalServer.
+func (b *Bot) DownloadFile(ctx context.Context, fileID string, w io.Writer, opts ...DownloadOption) (int64, error) {
+	var o downloadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	file, err := b.GetFileContext(ctx, fileID)
+	if err != nil {
+		return 0, err
+	}
+
+	if file.FilePath == "" {
+		return 0, fmt.Errorf("bot: file %s can't be downloaded", fileID)
+	}
+
+	if o.maxSize > 0 && file.FileSize > o.maxSize {
+		return 0, ErrFileTooLarge
+	}
+
+	// without a limit, copy everything up to EOF
+	limit := int64(-1)
+	if o.maxSize > 0 {
+		limit = o.maxSize - o.offset
+		if limit < 0 {
+			return 0, ErrFileTooLarge
+		}
+	}
+
+	if b.isLocalFile(file.FilePath) {
+		return copyLocalFile(file.FilePath, o.offset, w, limit)
+	}
+
+	var written int64
+	for attempt := 1; ; attempt++ {
+		remaining := limit
+		if limit >= 0 {
+			remaining -= written
+		}
+
+		n, err := b.download(ctx, b.FileURL(file.FilePath), o.offset+written, w, remaining)
+		written += n
+		if err == nil {
+			return written, nil
+		}
+
+		wait, ok := b.RetryPolicy.retry(attempt, "downloadFile", err)
+		if !ok {
+			return written, err
+		}
+
+		if b.Debug {
+			log.Printf("resuming download of %s at %d in %s: %s\n", file.FilePath, o.offset+written, wait, err)
+		}
+
+		if !b.wait(ctx, wait) {
+			return written, err
+		}
+	}
+}
+
+// download copies the file at fileURL to w, starting at offset. A negative limit means no limit.
+func (b *Bot) download(ctx context.Context, fileURL string, offset int64, w io.Writer, limit int64) (int64, error) {
+	r, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if offset > 0 {
+		r.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := b.client.Do(r)
+	if err != nil {
+		return 0, b.redactToken(err)
+	}
+	defer resp.Body.Close()
+
+	body := io.Reader(&downloadReader{r: resp.Body, url: b.redactURL(fileURL)})
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		if offset > 0 {
+			// the server ignored the range, so skip what was already downloaded
+			if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+				return 0, err
+			}
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// offset is at the end of the file, so there's nothing left to download
+		return 0, nil
+	default:
+		return 0, &APIError{Method: "downloadFile", ErrorCode: resp.StatusCode, Description: resp.Status}
+	}
+
+	return copyLimit(w, body, limit)
+}
+
+func copyLocalFile(filePath string, offset int64, w io.Writer, limit int64) (int64, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return copyLimit(w, f, limit)
+}
+
+// copyLimit copies r to w, but fails with ErrFileTooLarge instead of writing more than limit
+// bytes. A negative limit means no limit.
+func copyLimit(w io.Writer, r io.Reader, limit int64) (int64, error) {
+	if limit < 0 {
+		return io.Copy(w, r)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(r, limit))
+	if err != nil {
+		return n, err
+	}
+
+	var extra [1]byte
+	if m, _ := io.ReadFull(r, extra[:]); m > 0 {
+		return n, ErrFileTooLarge
+	}
+
+	return n, nil
+}
+
+// downloadReader reports errors reading the response body as network errors, so the
+// RetryPolicy resumes the download.
+type downloadReader struct {
+	r   io.Reader
+	url string
+}
+
+func (d *downloadReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = &url.Error{Op: "Get", URL: d.url, Err: err}
+	}
+
+	return n, err
+}
@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fileContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newFileServerBot returns a bot whose fake server answers getFile and serves fileContent. The
+// first download is cut off after breakAfter bytes, unless breakAfter is zero.
+func newFileServerBot(t *testing.T, breakAfter int) (*Bot, *[]string) {
+	var mu sync.Mutex
+	var ranges []string
+	broken := false
+
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botmysecrettoken/getFile":
+			fmt.Fprintf(w, `{"ok":true,"result":{"file_id":"abc","file_unique_id":"u","file_size":%d,"file_path":"documents/file_1.txt"}}`, len(fileContent))
+			return
+		case "/file/botmysecrettoken/documents/file_1.txt":
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			return
+		}
+
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		breakNow := breakAfter > 0 && !broken
+		broken = true
+		mu.Unlock()
+
+		if breakNow {
+			w.Header().Set("Content-Length", fmt.Sprint(len(fileContent)))
+			w.Write([]byte(fileContent[:breakAfter]))
+			w.(http.Flusher).Flush()
+
+			// drop the connection in the middle of the body
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+
+		http.ServeContent(w, r, "file_1.txt", time.Time{}, strings.NewReader(fileContent))
+	})
+
+	return b, &ranges
+}
+
+func TestDownloadFile(t *testing.T) {
+	b, ranges := newFileServerBot(t, 0)
+
+	file, err := b.GetFile("abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "documents/file_1.txt", file.FilePath)
+	assert.Equal(t, int64(len(fileContent)), file.FileSize)
+
+	var buf bytes.Buffer
+	n, err := b.DownloadFile(context.Background(), "abc", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(fileContent)), n)
+	assert.Equal(t, fileContent, buf.String())
+
+	buf.Reset()
+	n, err = b.DownloadFile(context.Background(), "abc", &buf, WithOffset(30))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "uvwxyz", buf.String())
+	assert.Equal(t, []string{"", "bytes=30-"}, *ranges)
+
+	buf.Reset()
+	_, err = b.DownloadFile(context.Background(), "abc", &buf, WithMaxSize(10))
+	assert.Equal(t, ErrFileTooLarge, err)
+	assert.Zero(t, buf.Len())
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	b, ranges := newFileServerBot(t, 10)
+	b.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseDelay: 1})
+
+	var buf bytes.Buffer
+	n, err := b.DownloadFile(context.Background(), "abc", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(fileContent)), n)
+	assert.Equal(t, fileContent, buf.String())
+	assert.Equal(t, []string{"", "bytes=10-"}, *ranges)
+}
+
+func TestDownloadFileErrorHidesToken(t *testing.T) {
+	b, _ := newFileServerBot(t, 10)
+
+	// the body breaks off, and there is no RetryPolicy to resume it
+	_, err := b.DownloadFile(context.Background(), "abc", ioutil.Discard)
+	var urlErr *url.Error
+	if assert.ErrorAs(t, err, &urlErr) {
+		assert.Contains(t, urlErr.URL, "/file/bot<token>/documents/file_1.txt")
+		assert.NotContains(t, err.Error(), "mysecrettoken")
+	}
+
+	// the server can't be reached at all
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	b = New("Test_Bot", "mysecrettoken", WithBaseURL(server.URL))
+	_, err = b.GetFile("abc")
+	if assert.ErrorAs(t, err, &urlErr) {
+		assert.NotContains(t, err.Error(), "mysecrettoken")
+	}
+}
+
+func TestDownloadFileLocal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file_1.txt")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(fileContent), 0600))
+
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botmysecrettoken/getFile", r.URL.Path)
+		fmt.Fprintf(w, `{"ok":true,"result":{"file_id":"abc","file_unique_id":"u","file_path":%q}}`, path)
+	})
+	WithLocalServer()(b)
+
+	var buf bytes.Buffer
+	n, err := b.DownloadFile(context.Background(), "abc", &buf, WithOffset(26), WithMaxSize(36))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+	assert.Equal(t, "qrstuvwxyz", buf.String())
+}
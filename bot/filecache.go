@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+)
+
+// FileCache is an interface for storing the file_id Telegram assigned to uploaded content. When
+// the Bot has a FileCache, content that was uploaded before is sent by its file_id instead.
+type FileCache interface {
+	// FileID should return the file_id stored for key. If there is none, but otherwise there was
+	// no error, ("", nil) should be returned.
+	FileID(key string) (string, error)
+
+	// SetFileID should store fileID for key. An empty fileID should remove key, so a file_id
+	// Telegram no longer accepts isn't used again.
+	SetFileID(key, fileID string) error
+}
+
+// SetFileCache sets the cache used to avoid uploading the same content twice. A nil cache
+// disables caching.
+func (b *Bot) SetFileCache(c FileCache) {
+	b.FileCache = c
+}
+
+// postMedia sends msg like genericPost. If msg uploads media and a FileCache is set, content
+// which was uploaded before is sent by its file_id, and the file_id of new content is stored.
+func (b *Bot) postMedia(ctx context.Context, endpoint string, msg interface{}, result *MessageResult) error {
+	m, ok := msg.(mediaPayload)
+	if !ok || b.FileCache == nil {
+		return b.genericPost(ctx, endpoint, msg, result)
+	}
+
+	field, file, _ := m.media()
+	key, ok, err := fileCacheKey(field, file)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return b.genericPost(ctx, endpoint, msg, result)
+	}
+
+	fileID, err := b.FileCache.FileID(key)
+	if err != nil {
+		return err
+	}
+
+	if fileID != "" {
+		err := b.genericPost(ctx, endpoint, m.withMedia(FromFileID(fileID)), result)
+		if !IsInvalidFileID(err) {
+			return err
+		}
+
+		// the file_id is no longer valid, so forget it and upload the content again
+		if err := b.FileCache.SetFileID(key, ""); err != nil {
+			return err
+		}
+
+		*result = MessageResult{}
+	}
+
+	if err := b.genericPost(ctx, endpoint, msg, result); err != nil {
+		return err
+	}
+
+	if fileID := mediaFileID(result.Result, field); fileID != "" {
+		return b.FileCache.SetFileID(key, fileID)
+	}
+
+	return nil
+}
+
+// fileCacheKey returns a hash of the content of an upload, prefixed with the kind of media since
+// a file_id can't be used with every method. It returns false if the content can't be read
+// without consuming it.
+func fileCacheKey(field string, f *InputFile) (string, bool, error) {
+	if !f.IsUpload() {
+		return "", false, nil
+	}
+
+	h := sha256.New()
+	switch {
+	case f.path != "":
+		r, err := os.Open(f.path)
+		if err != nil {
+			return "", false, err
+		}
+		defer r.Close()
+
+		if _, err := io.Copy(h, r); err != nil {
+			return "", false, err
+		}
+	case f.reader != nil:
+		seeker, ok := f.reader.(io.ReadSeeker)
+		if !ok {
+			return "", false, nil
+		}
+
+		if err := hashSeeker(h, seeker); err != nil {
+			return "", false, err
+		}
+	default:
+		h.Write(f.data)
+	}
+
+	return field + ":" + hex.EncodeToString(h.Sum(nil)), true, nil
+}
+
+// hashSeeker writes the rest of r to h and then seeks back to where r was.
+func hashSeeker(h hash.Hash, r io.ReadSeeker) error {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+
+	_, err = r.Seek(offset, io.SeekStart)
+	return err
+}
+
+// mediaFileID returns the file_id of the media sent in field of m. For photos, the largest size
+// is used.
+func mediaFileID(m *Message, field string) string {
+	if m == nil {
+		return ""
+	}
+
+	switch field {
+	case "photo":
+		if p := m.LargestPhoto(); p != nil {
+			return p.FileID
+		}
+	case "document":
+		if m.Document != nil {
+			return m.Document.FileID
+		}
+	case "audio":
+		if m.Audio != nil {
+			return m.Audio.FileID
+		}
+	case "video":
+		if m.Video != nil {
+			return m.Video.FileID
+		}
+	case "animation":
+		if m.Animation != nil {
+			return m.Animation.FileID
+		}
+	case "voice":
+		if m.Voice != nil {
+			return m.Voice.FileID
+		}
+	case "video_note":
+		if m.VideoNote != nil {
+			return m.VideoNote.FileID
+		}
+	case "sticker":
+		if m.Sticker != nil {
+			return m.Sticker.FileID
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapFileCache map[string]string
+
+func (m mapFileCache) FileID(key string) (string, error) {
+	return m[key], nil
+}
+
+func (m mapFileCache) SetFileID(key, fileID string) error {
+	if fileID == "" {
+		delete(m, key)
+	} else {
+		m[key] = fileID
+	}
+
+	return nil
+}
+
+func TestFileCache(t *testing.T) {
+	var sent []string
+	staleFileID := false
+	badRequest := false
+
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, _, err := r.FormFile("document")
+			assert.NoError(t, err)
+			contents, _ := ioutil.ReadAll(file)
+			sent = append(sent, "upload:"+string(contents))
+
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":1,"document":{"file_id":"doc-1","file_unique_id":"u"}}}`)
+			return
+		}
+
+		var msg struct {
+			Document string `json:"document"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		sent = append(sent, "file_id:"+msg.Document)
+
+		if badRequest {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`)
+			return
+		}
+
+		if staleFileID {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier/HTTP URL specified"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":2}}`)
+	})
+
+	cache := mapFileCache{}
+	b.SetFileCache(cache)
+
+	assert.NoError(t, b.PostSendDocument(&SendDocument{ChatID: 1000, Document: FromBytes("logo.png", []byte("logo"))}))
+	assert.NoError(t, b.PostSendDocument(&SendDocument{ChatID: 1000, Document: FromReader("logo.png", strings.NewReader("logo"))}))
+	assert.Equal(t, []string{"upload:logo", "file_id:doc-1"}, sent)
+	assert.Len(t, cache, 1)
+
+	// other errors are returned without uploading the content again
+	sent = nil
+	badRequest = true
+	err := b.PostSendDocument(&SendDocument{ChatID: 1000, Document: FromBytes("logo.png", []byte("logo")), ParseMode: ParseModeHTML, Caption: "<b"})
+	assert.EqualError(t, err, "bot: failed request to sendDocument { 400, Bad Request: can't parse entities }")
+	assert.Equal(t, []string{"file_id:doc-1"}, sent)
+	assert.Len(t, cache, 1)
+	badRequest = false
+
+	// a file_id Telegram no longer accepts is replaced by uploading the content again
+	sent = nil
+	staleFileID = true
+	assert.NoError(t, b.PostSendDocument(&SendDocument{ChatID: 1000, Document: FromBytes("logo.png", []byte("logo"))}))
+	assert.Equal(t, []string{"file_id:doc-1", "upload:logo"}, sent)
+
+	// different content is uploaded
+	sent = nil
+	assert.NoError(t, b.PostSendDocument(&SendDocument{ChatID: 1000, Document: FromBytes("report.pdf", []byte("report"))}))
+	assert.Equal(t, []string{"upload:report"}, sent)
+	assert.Len(t, cache, 2)
+}
+
+func TestMediaFileID(t *testing.T) {
+	var m Message
+	assert.NoError(t, json.Unmarshal([]byte(`{"message_id":1,"photo":[{"file_id":"small"},{"file_id":"large"}],"video":{"file_id":"video"}}`), &m))
+
+	assert.Equal(t, "large", mediaFileID(&m, "photo"))
+	assert.Equal(t, "video", mediaFileID(&m, "video"))
+	assert.Equal(t, "", mediaFileID(&m, "document"))
+	assert.Equal(t, "", mediaFileID(nil, "document"))
+}
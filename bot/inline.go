@@ -0,0 +1,175 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+// MaxInlineQueryResults is the most results Telegram accepts in one answer to an inline query.
+const MaxInlineQueryResults = 50
+
+// InlineQuery represents an incoming inline query, sent when a user types "@YourBot query".
+type InlineQuery struct {
+	ID       string    `json:"id"`
+	From     *User     `json:"from"`
+	Query    string    `json:"query"`
+	Offset   string    `json:"offset"`
+	ChatType string    `json:"chat_type,omitempty"` // sender, private, group, supergroup or channel
+	Location *Location `json:"location,omitempty"`
+}
+
+// ChosenInlineResult represents a result of an inline query that was chosen by the user and
+// sent to their chat partner. Telegram only reports these if inline feedback is enabled for
+// the bot.
+type ChosenInlineResult struct {
+	ResultID        string    `json:"result_id"`
+	From            *User     `json:"from"`
+	Location        *Location `json:"location,omitempty"`
+	InlineMessageID string    `json:"inline_message_id,omitempty"`
+	Query           string    `json:"query"`
+}
+
+// AnswerInlineQuery represents the payload that needs to be sent to Telegram's answerInlineQuery
+// method.
+type AnswerInlineQuery struct {
+	InlineQueryID string              `json:"inline_query_id"`
+	Results       []InlineQueryResult `json:"results"`
+
+	// CacheTime is how many seconds the results may be cached on Telegram's servers. Zero uses
+	// Telegram's default of 300 seconds.
+	CacheTime int `json:"cache_time,omitempty"`
+
+	// IsPersonal caches the results only for the user who sent the query.
+	IsPersonal bool `json:"is_personal,omitempty"`
+
+	// NextOffset is sent back in the next query when the user scrolls to the end of the results.
+	// It is empty if there are no more results.
+	NextOffset string `json:"next_offset,omitempty"`
+
+	Button *InlineQueryResultsButton `json:"button,omitempty"`
+}
+
+// InlineQueryResultsButton represents a button shown above inline query results.
+type InlineQueryResultsButton struct {
+	Text           string      `json:"text"`
+	WebApp         *WebAppInfo `json:"web_app,omitempty"`
+	StartParameter string      `json:"start_parameter,omitempty"`
+}
+
+// SetInlineQueryHandler will register a handler to be called for inline queries. args is the
+// query text.
+func (b *Bot) SetInlineQueryHandler(h Handler) {
+	b.InlineQueryHandler = h
+}
+
+// SetChosenInlineResultHandler will register a handler to be called when a user picked a result
+// of an inline query. args is the ID of the result.
+func (b *Bot) SetChosenInlineResultHandler(h Handler) {
+	b.ChosenInlineResultHandler = h
+}
+
+// AnswerInlineQuery will send the results of an inline query.
+func (b *Bot) AnswerInlineQuery(answer *AnswerInlineQuery) error {
+	return b.AnswerInlineQueryContext(context.Background(), answer)
+}
+
+// AnswerInlineQueryContext is like AnswerInlineQuery, but with a context.
+func (b *Bot) AnswerInlineQueryContext(ctx context.Context, answer *AnswerInlineQuery) error {
+	var result GenericResult
+	return b.genericPost(ctx, "answerInlineQuery", answer, &result)
+}
+
+// Paginate sets Results to the page of results the offset of an inline query asks for, with at
+// most pageSize results, and NextOffset to the offset of the following page.
+//
+// Example:
+//
+//	answer := &bot.AnswerInlineQuery{InlineQueryID: q.ID}
+//	b.AnswerInlineQueryContext(ctx, answer.Paginate(results, q.Offset, 20))
+func (a *AnswerInlineQuery) Paginate(results []InlineQueryResult, offset string, pageSize int) *AnswerInlineQuery {
+	if pageSize <= 0 || pageSize > MaxInlineQueryResults {
+		pageSize = MaxInlineQueryResults
+	}
+
+	start := InlineOffset(offset)
+	if start > len(results) {
+		start = len(results)
+	}
+
+	end := start + pageSize
+	if end > len(results) {
+		end = len(results)
+	}
+
+	a.Results = results[start:end]
+	a.NextOffset = ""
+	if end < len(results) {
+		a.NextOffset = strconv.Itoa(end)
+	}
+
+	return a
+}
+
+// InlineOffset returns the position an inline query's offset points to, for results that are
+// loaded page by page, e.g. from a database. An empty or invalid offset is 0.
+func InlineOffset(offset string) int {
+	n, err := strconv.Atoi(offset)
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// NextInlineOffset returns the NextOffset for a page which started at offset and had found
+// results, out of at most pageSize. A page that isn't full is the last one, so "" is returned.
+func NextInlineOffset(offset string, found, pageSize int) string {
+	if found < pageSize {
+		return ""
+	}
+
+	return strconv.Itoa(InlineOffset(offset) + found)
+}
+
+// MarshalJSON encodes the answer with the type of every result. It has a value receiver, so
+// results get their type whether the answer is passed by value or as a pointer.
+func (a AnswerInlineQuery) MarshalJSON() ([]byte, error) {
+	type answerInlineQuery AnswerInlineQuery
+
+	results := make([]json.RawMessage, len(a.Results))
+	for i, r := range a.Results {
+		b, err := marshalInlineQueryResult(r)
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = b
+	}
+
+	return json.Marshal(&struct {
+		*answerInlineQuery
+		Results []json.RawMessage `json:"results"`
+	}{(*answerInlineQuery)(&a), results})
+}
+
+// dispatchInline passes inline queries and chosen inline results to their handlers.
+func (b *Bot) dispatchInline(ctx context.Context, ur *UpdateResponse) error {
+	if b.Debug {
+		log.Printf("received in %s: %s\n", b.BotName, ur.String())
+	}
+
+	switch {
+	case ur.InlineQuery != nil:
+		if h := b.InlineQueryHandler; h != nil {
+			h(ctx, b, ur, ur.InlineQuery.Query)
+		}
+	case ur.ChosenInlineResult != nil:
+		if h := b.ChosenInlineResultHandler; h != nil {
+			h(ctx, b, ur, ur.ChosenInlineResult.ResultID)
+		}
+	}
+
+	return nil
+}
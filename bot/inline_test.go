@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatchInline(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+
+	var calls []string
+	b.SetInlineQueryHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		calls = append(calls, "query "+args+" from "+fmt.Sprint(ur.FromID()))
+	})
+	b.SetChosenInlineResultHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		calls = append(calls, "chosen "+args+" from "+fmt.Sprint(ur.FromID()))
+	})
+
+	assert.NoError(t, b.Dispatch(context.Background(), &UpdateResponse{
+		InlineQuery: &InlineQuery{ID: "q1", From: &User{ID: 1}, Query: "cats"},
+	}))
+	assert.NoError(t, b.Dispatch(context.Background(), &UpdateResponse{
+		ChosenInlineResult: &ChosenInlineResult{ResultID: "r2", From: &User{ID: 3}, Query: "cats"},
+	}))
+
+	assert.Equal(t, []string{"query cats from 1", "chosen r2 from 3"}, calls)
+}
+
+func TestAnswerInlineQuery(t *testing.T) {
+	var body string
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botmysecrettoken/answerInlineQuery", r.URL.Path)
+
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(data)
+
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	})
+
+	article := NewInlineQueryResultArticle("1", "Hello", &InputTextMessageContent{MessageText: "Hello, world"})
+	article.ReplyMarkup = &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{NewCallbackButton("Like", "like")}},
+	}
+
+	err := b.AnswerInlineQuery(&AnswerInlineQuery{
+		InlineQueryID: "q1",
+		Results: []InlineQueryResult{
+			article,
+			NewInlineQueryResultCachedSticker("2", "sticker-id"),
+			NewInlineQueryResultLocation("3", "Berlin", 52.52, 13.405),
+		},
+		CacheTime:  10,
+		IsPersonal: true,
+		NextOffset: "3",
+	})
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"inline_query_id": "q1",
+		"results": [
+			{
+				"type": "article",
+				"id": "1",
+				"title": "Hello",
+				"input_message_content": {"message_text": "Hello, world"},
+				"reply_markup": {"inline_keyboard": [[{"text": "Like", "callback_data": "like"}]]}
+			},
+			{"type": "sticker", "id": "2", "sticker_file_id": "sticker-id"},
+			{"type": "location", "id": "3", "title": "Berlin", "latitude": 52.52, "longitude": 13.405}
+		],
+		"cache_time": 10,
+		"is_personal": true,
+		"next_offset": "3"
+	}`, body)
+}
+
+func TestAnswerInlineQueryMarshalJSON(t *testing.T) {
+	answer := AnswerInlineQuery{
+		InlineQueryID: "q1",
+		Results:       []InlineQueryResult{NewInlineQueryResultCachedSticker("1", "sticker-id")},
+	}
+
+	// results are typed whether the answer is marshalled by value or as a pointer
+	want := `{"inline_query_id":"q1","results":[{"type":"sticker","id":"1","sticker_file_id":"sticker-id"}]}`
+	for _, v := range []interface{}{answer, &answer} {
+		data, err := json.Marshal(v)
+		assert.NoError(t, err)
+		assert.JSONEq(t, want, string(data))
+	}
+
+	var sticker *InlineQueryResultCachedSticker
+	for _, r := range []InlineQueryResult{nil, sticker} {
+		answer.Results = []InlineQueryResult{r}
+		_, err := json.Marshal(answer)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "bot: inline query result is nil")
+	}
+}
+
+func TestInlineQueryPagination(t *testing.T) {
+	results := make([]InlineQueryResult, 5)
+	for i := range results {
+		results[i] = NewInlineQueryResultCachedPhoto(fmt.Sprint(i), "photo-id")
+	}
+
+	answer := (&AnswerInlineQuery{InlineQueryID: "q1"}).Paginate(results, "", 2)
+	assert.Equal(t, results[0:2], answer.Results)
+	assert.Equal(t, "2", answer.NextOffset)
+
+	answer.Paginate(results, answer.NextOffset, 2)
+	assert.Equal(t, results[2:4], answer.Results)
+	assert.Equal(t, "4", answer.NextOffset)
+
+	answer.Paginate(results, answer.NextOffset, 2)
+	assert.Equal(t, results[4:], answer.Results)
+	assert.Equal(t, "", answer.NextOffset)
+
+	answer.Paginate(results, "100", 2)
+	assert.Empty(t, answer.Results)
+	assert.Equal(t, "", answer.NextOffset)
+
+	assert.Equal(t, 0, InlineOffset(""))
+	assert.Equal(t, 0, InlineOffset("nope"))
+	assert.Equal(t, 0, InlineOffset("-5"))
+	assert.Equal(t, 20, InlineOffset("20"))
+
+	assert.Equal(t, "40", NextInlineOffset("20", 20, 20))
+	assert.Equal(t, "", NextInlineOffset("20", 7, 20))
+}
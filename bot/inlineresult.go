@@ -0,0 +1,471 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// InlineQueryResult represents one result of an inline query. It is implemented by the
+// InlineQueryResult types, such as *InlineQueryResultArticle. Results with a URL link to content
+// Telegram downloads, while Cached results send a file that is already stored on Telegram's servers.
+type InlineQueryResult interface {
+	inlineQueryResultType() string
+}
+
+// InputMessageContent represents the content of the message sent when an inline query result
+// is chosen, instead of the result's own media. It is implemented by *InputTextMessageContent,
+// *InputLocationMessageContent, *InputVenueMessageContent and *InputContactMessageContent.
+type InputMessageContent interface {
+	inputMessageContent()
+}
+
+// InlineQueryResultArticle represents a link to an article or web page.
+type InlineQueryResultArticle struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	InputMessageContent InputMessageContent   `json:"input_message_content"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultPhoto represents a link to a photo.
+type InlineQueryResultPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoURL            string                `json:"photo_url"` // JPEG, at most 5 MB
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	PhotoWidth          int                   `json:"photo_width,omitempty"`
+	PhotoHeight         int                   `json:"photo_height,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultGif represents a link to an animated GIF.
+type InlineQueryResultGif struct {
+	ID                  string                `json:"id"`
+	GifURL              string                `json:"gif_url"`
+	GifWidth            int                   `json:"gif_width,omitempty"`
+	GifHeight           int                   `json:"gif_height,omitempty"`
+	GifDuration         int                   `json:"gif_duration,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	ThumbnailMimeType   string                `json:"thumbnail_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultMpeg4Gif represents a link to an H.264/MPEG-4 AVC video without sound.
+type InlineQueryResultMpeg4Gif struct {
+	ID                  string                `json:"id"`
+	Mpeg4URL            string                `json:"mpeg4_url"`
+	Mpeg4Width          int                   `json:"mpeg4_width,omitempty"`
+	Mpeg4Height         int                   `json:"mpeg4_height,omitempty"`
+	Mpeg4Duration       int                   `json:"mpeg4_duration,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	ThumbnailMimeType   string                `json:"thumbnail_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultVideo represents a link to a video file or a page with an embedded video
+// player. A video player must be sent with an InputMessageContent.
+type InlineQueryResultVideo struct {
+	ID                  string                `json:"id"`
+	VideoURL            string                `json:"video_url"`
+	MimeType            string                `json:"mime_type"` // text/html or video/mp4
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VideoWidth          int                   `json:"video_width,omitempty"`
+	VideoHeight         int                   `json:"video_height,omitempty"`
+	VideoDuration       int                   `json:"video_duration,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultAudio represents a link to an MP3 audio file.
+type InlineQueryResultAudio struct {
+	ID                  string                `json:"id"`
+	AudioURL            string                `json:"audio_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	Performer           string                `json:"performer,omitempty"`
+	AudioDuration       int                   `json:"audio_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultVoice represents a link to a voice recording in an OGG container encoded
+// with OPUS.
+type InlineQueryResultVoice struct {
+	ID                  string                `json:"id"`
+	VoiceURL            string                `json:"voice_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VoiceDuration       int                   `json:"voice_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultDocument represents a link to a PDF or ZIP file.
+type InlineQueryResultDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	DocumentURL         string                `json:"document_url"`
+	MimeType            string                `json:"mime_type"` // application/pdf or application/zip
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultLocation represents a location on a map.
+type InlineQueryResultLocation struct {
+	ID                   string                `json:"id"`
+	Latitude             float64               `json:"latitude"`
+	Longitude            float64               `json:"longitude"`
+	Title                string                `json:"title"`
+	HorizontalAccuracy   float64               `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int                   `json:"live_period,omitempty"`
+	Heading              int                   `json:"heading,omitempty"`
+	ProximityAlertRadius int                   `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent  InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL         string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth       int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight      int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultVenue represents a venue.
+type InlineQueryResultVenue struct {
+	ID                  string                `json:"id"`
+	Latitude            float64               `json:"latitude"`
+	Longitude           float64               `json:"longitude"`
+	Title               string                `json:"title"`
+	Address             string                `json:"address"`
+	FoursquareID        string                `json:"foursquare_id,omitempty"`
+	FoursquareType      string                `json:"foursquare_type,omitempty"`
+	GooglePlaceID       string                `json:"google_place_id,omitempty"`
+	GooglePlaceType     string                `json:"google_place_type,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultContact represents a contact with a phone number.
+type InlineQueryResultContact struct {
+	ID                  string                `json:"id"`
+	PhoneNumber         string                `json:"phone_number"`
+	FirstName           string                `json:"first_name"`
+	LastName            string                `json:"last_name,omitempty"`
+	VCard               string                `json:"vcard,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultCachedPhoto represents a photo stored on Telegram's servers.
+type InlineQueryResultCachedPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoFileID         string                `json:"photo_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedGif represents an animated GIF stored on Telegram's servers.
+type InlineQueryResultCachedGif struct {
+	ID                  string                `json:"id"`
+	GifFileID           string                `json:"gif_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedMpeg4Gif represents a video without sound stored on Telegram's servers.
+type InlineQueryResultCachedMpeg4Gif struct {
+	ID                  string                `json:"id"`
+	Mpeg4FileID         string                `json:"mpeg4_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedSticker represents a sticker stored on Telegram's servers.
+type InlineQueryResultCachedSticker struct {
+	ID                  string                `json:"id"`
+	StickerFileID       string                `json:"sticker_file_id"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedDocument represents a file stored on Telegram's servers.
+type InlineQueryResultCachedDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	DocumentFileID      string                `json:"document_file_id"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVideo represents a video stored on Telegram's servers.
+type InlineQueryResultCachedVideo struct {
+	ID                  string                `json:"id"`
+	VideoFileID         string                `json:"video_file_id"`
+	Title               string                `json:"title"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVoice represents a voice message stored on Telegram's servers.
+type InlineQueryResultCachedVoice struct {
+	ID                  string                `json:"id"`
+	VoiceFileID         string                `json:"voice_file_id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedAudio represents an MP3 audio file stored on Telegram's servers.
+type InlineQueryResultCachedAudio struct {
+	ID                  string                `json:"id"`
+	AudioFileID         string                `json:"audio_file_id"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (*InlineQueryResultArticle) inlineQueryResultType() string        { return "article" }
+func (*InlineQueryResultPhoto) inlineQueryResultType() string          { return "photo" }
+func (*InlineQueryResultGif) inlineQueryResultType() string            { return "gif" }
+func (*InlineQueryResultMpeg4Gif) inlineQueryResultType() string       { return "mpeg4_gif" }
+func (*InlineQueryResultVideo) inlineQueryResultType() string          { return "video" }
+func (*InlineQueryResultAudio) inlineQueryResultType() string          { return "audio" }
+func (*InlineQueryResultVoice) inlineQueryResultType() string          { return "voice" }
+func (*InlineQueryResultDocument) inlineQueryResultType() string       { return "document" }
+func (*InlineQueryResultLocation) inlineQueryResultType() string       { return "location" }
+func (*InlineQueryResultVenue) inlineQueryResultType() string          { return "venue" }
+func (*InlineQueryResultContact) inlineQueryResultType() string        { return "contact" }
+func (*InlineQueryResultCachedPhoto) inlineQueryResultType() string    { return "photo" }
+func (*InlineQueryResultCachedGif) inlineQueryResultType() string      { return "gif" }
+func (*InlineQueryResultCachedMpeg4Gif) inlineQueryResultType() string { return "mpeg4_gif" }
+func (*InlineQueryResultCachedSticker) inlineQueryResultType() string  { return "sticker" }
+func (*InlineQueryResultCachedDocument) inlineQueryResultType() string { return "document" }
+func (*InlineQueryResultCachedVideo) inlineQueryResultType() string    { return "video" }
+func (*InlineQueryResultCachedVoice) inlineQueryResultType() string    { return "voice" }
+func (*InlineQueryResultCachedAudio) inlineQueryResultType() string    { return "audio" }
+
+// NewInlineQueryResultArticle returns an article which sends content when chosen.
+func NewInlineQueryResultArticle(id, title string, content InputMessageContent) *InlineQueryResultArticle {
+	return &InlineQueryResultArticle{ID: id, Title: title, InputMessageContent: content}
+}
+
+// NewInlineQueryResultPhoto returns a result which sends the photo at photoURL.
+func NewInlineQueryResultPhoto(id, photoURL, thumbnailURL string) *InlineQueryResultPhoto {
+	return &InlineQueryResultPhoto{ID: id, PhotoURL: photoURL, ThumbnailURL: thumbnailURL}
+}
+
+// NewInlineQueryResultGif returns a result which sends the GIF at gifURL.
+func NewInlineQueryResultGif(id, gifURL, thumbnailURL string) *InlineQueryResultGif {
+	return &InlineQueryResultGif{ID: id, GifURL: gifURL, ThumbnailURL: thumbnailURL}
+}
+
+// NewInlineQueryResultMpeg4Gif returns a result which sends the video without sound at mpeg4URL.
+func NewInlineQueryResultMpeg4Gif(id, mpeg4URL, thumbnailURL string) *InlineQueryResultMpeg4Gif {
+	return &InlineQueryResultMpeg4Gif{ID: id, Mpeg4URL: mpeg4URL, ThumbnailURL: thumbnailURL}
+}
+
+// NewInlineQueryResultVideo returns a result which sends the video at videoURL.
+func NewInlineQueryResultVideo(id, videoURL, mimeType, thumbnailURL, title string) *InlineQueryResultVideo {
+	return &InlineQueryResultVideo{ID: id, VideoURL: videoURL, MimeType: mimeType, ThumbnailURL: thumbnailURL, Title: title}
+}
+
+// NewInlineQueryResultAudio returns a result which sends the audio file at audioURL.
+func NewInlineQueryResultAudio(id, audioURL, title string) *InlineQueryResultAudio {
+	return &InlineQueryResultAudio{ID: id, AudioURL: audioURL, Title: title}
+}
+
+// NewInlineQueryResultVoice returns a result which sends the voice recording at voiceURL.
+func NewInlineQueryResultVoice(id, voiceURL, title string) *InlineQueryResultVoice {
+	return &InlineQueryResultVoice{ID: id, VoiceURL: voiceURL, Title: title}
+}
+
+// NewInlineQueryResultDocument returns a result which sends the file at documentURL.
+func NewInlineQueryResultDocument(id, title, documentURL, mimeType string) *InlineQueryResultDocument {
+	return &InlineQueryResultDocument{ID: id, Title: title, DocumentURL: documentURL, MimeType: mimeType}
+}
+
+// NewInlineQueryResultLocation returns a result which sends a location.
+func NewInlineQueryResultLocation(id, title string, latitude, longitude float64) *InlineQueryResultLocation {
+	return &InlineQueryResultLocation{ID: id, Title: title, Latitude: latitude, Longitude: longitude}
+}
+
+// NewInlineQueryResultVenue returns a result which sends a venue.
+func NewInlineQueryResultVenue(id, title, address string, latitude, longitude float64) *InlineQueryResultVenue {
+	return &InlineQueryResultVenue{ID: id, Title: title, Address: address, Latitude: latitude, Longitude: longitude}
+}
+
+// NewInlineQueryResultContact returns a result which sends a contact.
+func NewInlineQueryResultContact(id, phoneNumber, firstName string) *InlineQueryResultContact {
+	return &InlineQueryResultContact{ID: id, PhoneNumber: phoneNumber, FirstName: firstName}
+}
+
+// NewInlineQueryResultCachedPhoto returns a result which sends the photo with the given file_id.
+func NewInlineQueryResultCachedPhoto(id, fileID string) *InlineQueryResultCachedPhoto {
+	return &InlineQueryResultCachedPhoto{ID: id, PhotoFileID: fileID}
+}
+
+// NewInlineQueryResultCachedGif returns a result which sends the GIF with the given file_id.
+func NewInlineQueryResultCachedGif(id, fileID string) *InlineQueryResultCachedGif {
+	return &InlineQueryResultCachedGif{ID: id, GifFileID: fileID}
+}
+
+// NewInlineQueryResultCachedMpeg4Gif returns a result which sends the video without sound with
+// the given file_id.
+func NewInlineQueryResultCachedMpeg4Gif(id, fileID string) *InlineQueryResultCachedMpeg4Gif {
+	return &InlineQueryResultCachedMpeg4Gif{ID: id, Mpeg4FileID: fileID}
+}
+
+// NewInlineQueryResultCachedSticker returns a result which sends the sticker with the given file_id.
+func NewInlineQueryResultCachedSticker(id, fileID string) *InlineQueryResultCachedSticker {
+	return &InlineQueryResultCachedSticker{ID: id, StickerFileID: fileID}
+}
+
+// NewInlineQueryResultCachedDocument returns a result which sends the file with the given file_id.
+func NewInlineQueryResultCachedDocument(id, title, fileID string) *InlineQueryResultCachedDocument {
+	return &InlineQueryResultCachedDocument{ID: id, Title: title, DocumentFileID: fileID}
+}
+
+// NewInlineQueryResultCachedVideo returns a result which sends the video with the given file_id.
+func NewInlineQueryResultCachedVideo(id, title, fileID string) *InlineQueryResultCachedVideo {
+	return &InlineQueryResultCachedVideo{ID: id, Title: title, VideoFileID: fileID}
+}
+
+// NewInlineQueryResultCachedVoice returns a result which sends the voice message with the given
+// file_id.
+func NewInlineQueryResultCachedVoice(id, title, fileID string) *InlineQueryResultCachedVoice {
+	return &InlineQueryResultCachedVoice{ID: id, Title: title, VoiceFileID: fileID}
+}
+
+// NewInlineQueryResultCachedAudio returns a result which sends the audio file with the given
+// file_id.
+func NewInlineQueryResultCachedAudio(id, fileID string) *InlineQueryResultCachedAudio {
+	return &InlineQueryResultCachedAudio{ID: id, AudioFileID: fileID}
+}
+
+// InputTextMessageContent represents the content of a text message.
+type InputTextMessageContent struct {
+	MessageText           string          `json:"message_text"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity `json:"entities,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"`
+}
+
+// InputLocationMessageContent represents the content of a location message.
+type InputLocationMessageContent struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+// InputVenueMessageContent represents the content of a venue message.
+type InputVenueMessageContent struct {
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	Title           string  `json:"title"`
+	Address         string  `json:"address"`
+	FoursquareID    string  `json:"foursquare_id,omitempty"`
+	FoursquareType  string  `json:"foursquare_type,omitempty"`
+	GooglePlaceID   string  `json:"google_place_id,omitempty"`
+	GooglePlaceType string  `json:"google_place_type,omitempty"`
+}
+
+// InputContactMessageContent represents the content of a contact message.
+type InputContactMessageContent struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	VCard       string `json:"vcard,omitempty"`
+}
+
+func (*InputTextMessageContent) inputMessageContent()     {}
+func (*InputLocationMessageContent) inputMessageContent() {}
+func (*InputVenueMessageContent) inputMessageContent()    {}
+func (*InputContactMessageContent) inputMessageContent()  {}
+
+// marshalInlineQueryResult encodes r as JSON, along with the type of the result.
+func marshalInlineQueryResult(r InlineQueryResult) (json.RawMessage, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	if fields == nil {
+		return nil, errors.New("bot: inline query result is nil")
+	}
+
+	fields["type"], _ = json.Marshal(r.inlineQueryResultType())
+	return json.Marshal(fields)
+}
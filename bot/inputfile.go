@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// InputFile represents a file to send. It is either new content to upload, or a file Telegram
+// can get on its own: one it already has, referenced by file_id, or one at a public URL.
+//
+// An InputFile isn't changed by sending it, so it may be sent to several chats at once. A file
+// created with FromReader is the exception, since its reader can only be read by one upload at a
+// time.
+type InputFile struct {
+	name   string
+	path   string
+	reader io.Reader
+	data   []byte
+	fileID string
+	url    string
+
+	progress ProgressFunc
+}
+
+// ProgressFunc is called while a file is uploaded with the number of bytes written so far.
+type ProgressFunc func(written int64)
+
+// errNotReplayable is returned when an upload from a reader is repeated, but the reader can't
+// be rewound.
+var errNotReplayable = errors.New("bot: upload can't be repeated since the reader can't seek")
+
+// FromPath uploads the file at path.
+func FromPath(path string) *InputFile {
+	return &InputFile{name: filepath.Base(path), path: path}
+}
+
+// FromReader uploads everything read from r under the given file name. Sending the file consumes
+// r, so content sent more than once should use FromBytes or FromPath.
+func FromReader(name string, r io.Reader) *InputFile {
+	return &InputFile{name: name, reader: r}
+}
+
+// FromBytes uploads data under the given file name.
+func FromBytes(name string, data []byte) *InputFile {
+	return &InputFile{name: name, data: data}
+}
+
+// FromFileID sends a file which is already stored on Telegram's servers.
+func FromFileID(fileID string) *InputFile {
+	return &InputFile{fileID: fileID}
+}
+
+// FromURL lets Telegram download the file from url.
+func FromURL(url string) *InputFile {
+	return &InputFile{url: url}
+}
+
+// WithProgress sets a function which is called as the file's content is uploaded.
+func (f *InputFile) WithProgress(fn ProgressFunc) *InputFile {
+	f.progress = fn
+	return f
+}
+
+// IsUpload returns true if the file's content has to be uploaded with multipart/form-data.
+func (f *InputFile) IsUpload() bool {
+	return f != nil && f.fileID == "" && f.url == ""
+}
+
+// Name returns the file name used for an upload.
+func (f *InputFile) Name() string {
+	return f.name
+}
+
+// MarshalJSON encodes a file_id or URL as a string. Uploads are encoded as null, since their
+// content is sent in a separate form field.
+func (f *InputFile) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.fileID != "":
+		return json.Marshal(f.fileID)
+	case f.url != "":
+		return json.Marshal(f.url)
+	}
+
+	return []byte("null"), nil
+}
+
+// open returns the content to upload. A reader is read from where it currently is; it is
+// rewound by the uploadReaders of the request when the upload is retried.
+func (f *InputFile) open() (io.ReadCloser, error) {
+	switch {
+	case f.path != "":
+		return os.Open(f.path)
+	case f.reader != nil:
+		return io.NopCloser(f.reader), nil
+	}
+
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+// writeInputFile copies the content of an upload into a form file field.
+func writeInputFile(w *multipart.Writer, field string, f *InputFile) error {
+	r, err := f.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	part, err := w.CreateFormFile(field, f.name)
+	if err != nil {
+		return err
+	}
+
+	if f.progress != nil {
+		part = &progressWriter{w: part, fn: f.progress}
+	}
+
+	_, err = io.Copy(part, r)
+	return err
+}
+
+type progressWriter struct {
+	w       io.Writer
+	fn      ProgressFunc
+	written int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.fn(p.written)
+
+	return n, err
+}
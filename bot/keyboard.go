@@ -0,0 +1,73 @@
+package bot
+
+// InlineKeyboardMarkup represents an inline keyboard that appears right next to the message it
+// belongs to. To send one, use NewInlineKeyboard or set ReplyMarkup.InlineKeyboard.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton represents one button of an inline keyboard. Exactly one of the optional
+// fields must be set.
+type InlineKeyboardButton struct {
+	Text         string      `json:"text"`
+	URL          string      `json:"url,omitempty"`
+	CallbackData string      `json:"callback_data,omitempty"` // 1-64 bytes
+	WebApp       *WebAppInfo `json:"web_app,omitempty"`
+	LoginURL     *LoginURL   `json:"login_url,omitempty"`
+
+	// SwitchInlineQuery and SwitchInlineQueryCurrentChat are pointers, since an empty query is
+	// valid and inserts only the bot's username.
+	SwitchInlineQuery            *string `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrentChat *string `json:"switch_inline_query_current_chat,omitempty"`
+}
+
+// WebAppInfo describes a Web App.
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+// LoginURL represents a button which authorizes the user on a website with Telegram Login.
+type LoginURL struct {
+	URL                string `json:"url"`
+	ForwardText        string `json:"forward_text,omitempty"`
+	BotUsername        string `json:"bot_username,omitempty"`
+	RequestWriteAccess bool   `json:"request_write_access,omitempty"`
+}
+
+// NewInlineKeyboard returns a ReplyMarkup with an inline keyboard made of rows.
+//
+// Example:
+//
+//	markup := bot.NewInlineKeyboard(
+//		[]bot.InlineKeyboardButton{bot.NewCallbackButton("Yes", "vote:yes"), bot.NewCallbackButton("No", "vote:no")},
+//		[]bot.InlineKeyboardButton{bot.NewURLButton("Results", "https://example.com/results")},
+//	)
+func NewInlineKeyboard(rows ...[]InlineKeyboardButton) *ReplyMarkup {
+	return &ReplyMarkup{InlineKeyboard: rows}
+}
+
+// NewCallbackButton returns a button which sends data to the bot in a callback query.
+func NewCallbackButton(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+// NewURLButton returns a button which opens url.
+func NewURLButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// NewSwitchInlineQueryButton returns a button which lets the user pick a chat and starts an
+// inline query to the bot there with query.
+func NewSwitchInlineQueryButton(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+// NewWebAppButton returns a button which opens the Web App at url.
+func NewWebAppButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// NewLoginURLButton returns a button which logs the user in to the website at url.
+func NewLoginURLButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, LoginURL: &LoginURL{URL: url}}
+}
@@ -0,0 +1,204 @@
+package bot
+
+import (
+	"context"
+	"mime/multipart"
+)
+
+// mediaPayload is implemented by payloads which send a single media file and an optional thumbnail.
+type mediaPayload interface {
+	media() (field string, file *InputFile, thumbnail *InputFile)
+
+	// withMedia returns a copy of the payload which sends file instead.
+	withMedia(file *InputFile) mediaPayload
+}
+
+func (m *SendDocument) media() (string, *InputFile, *InputFile) {
+	return "document", m.Document, m.Thumbnail
+}
+
+func (m *SendPhoto) media() (string, *InputFile, *InputFile) {
+	return "photo", m.Photo, nil
+}
+
+func (m *SendAudio) media() (string, *InputFile, *InputFile) {
+	return "audio", m.Audio, m.Thumbnail
+}
+
+func (m *SendVideo) media() (string, *InputFile, *InputFile) {
+	return "video", m.Video, m.Thumbnail
+}
+
+func (m *SendAnimation) media() (string, *InputFile, *InputFile) {
+	return "animation", m.Animation, m.Thumbnail
+}
+
+func (m *SendVoice) media() (string, *InputFile, *InputFile) {
+	return "voice", m.Voice, nil
+}
+
+func (m *SendVideoNote) media() (string, *InputFile, *InputFile) {
+	return "video_note", m.VideoNote, m.Thumbnail
+}
+
+func (m *SendSticker) media() (string, *InputFile, *InputFile) {
+	return "sticker", m.Sticker, nil
+}
+
+func (m *SendDocument) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Document = file
+	return &c
+}
+
+func (m *SendPhoto) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Photo = file
+	return &c
+}
+
+func (m *SendAudio) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Audio = file
+	return &c
+}
+
+func (m *SendVideo) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Video = file
+	return &c
+}
+
+func (m *SendAnimation) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Animation = file
+	return &c
+}
+
+func (m *SendVoice) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Voice = file
+	return &c
+}
+
+func (m *SendVideoNote) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.VideoNote = file
+	return &c
+}
+
+func (m *SendSticker) withMedia(file *InputFile) mediaPayload {
+	c := *m
+	c.Sticker = file
+	return &c
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendDocument) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendPhoto) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendAudio) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendVideo) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendAnimation) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendVoice) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendVideoNote) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendSticker) EncodeMultipart(w *multipart.Writer) error {
+	return encodeMedia(w, m)
+}
+
+// PostSendPhoto will send a photo and return the result from the server.
+func (b *Bot) PostSendPhoto(msg *SendPhoto) (*MessageResult, error) {
+	return b.PostSendPhotoContext(context.Background(), msg)
+}
+
+// PostSendPhotoContext is like PostSendPhoto, but with a context.
+func (b *Bot) PostSendPhotoContext(ctx context.Context, msg *SendPhoto) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendPhoto", msg)
+}
+
+// PostSendAudio will send an audio file and return the result from the server.
+func (b *Bot) PostSendAudio(msg *SendAudio) (*MessageResult, error) {
+	return b.PostSendAudioContext(context.Background(), msg)
+}
+
+// PostSendAudioContext is like PostSendAudio, but with a context.
+func (b *Bot) PostSendAudioContext(ctx context.Context, msg *SendAudio) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendAudio", msg)
+}
+
+// PostSendVideo will send a video and return the result from the server.
+func (b *Bot) PostSendVideo(msg *SendVideo) (*MessageResult, error) {
+	return b.PostSendVideoContext(context.Background(), msg)
+}
+
+// PostSendVideoContext is like PostSendVideo, but with a context.
+func (b *Bot) PostSendVideoContext(ctx context.Context, msg *SendVideo) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendVideo", msg)
+}
+
+// PostSendAnimation will send an animation and return the result from the server.
+func (b *Bot) PostSendAnimation(msg *SendAnimation) (*MessageResult, error) {
+	return b.PostSendAnimationContext(context.Background(), msg)
+}
+
+// PostSendAnimationContext is like PostSendAnimation, but with a context.
+func (b *Bot) PostSendAnimationContext(ctx context.Context, msg *SendAnimation) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendAnimation", msg)
+}
+
+// PostSendVoice will send a voice message and return the result from the server.
+func (b *Bot) PostSendVoice(msg *SendVoice) (*MessageResult, error) {
+	return b.PostSendVoiceContext(context.Background(), msg)
+}
+
+// PostSendVoiceContext is like PostSendVoice, but with a context.
+func (b *Bot) PostSendVoiceContext(ctx context.Context, msg *SendVoice) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendVoice", msg)
+}
+
+// PostSendVideoNote will send a video note and return the result from the server.
+func (b *Bot) PostSendVideoNote(msg *SendVideoNote) (*MessageResult, error) {
+	return b.PostSendVideoNoteContext(context.Background(), msg)
+}
+
+// PostSendVideoNoteContext is like PostSendVideoNote, but with a context.
+func (b *Bot) PostSendVideoNoteContext(ctx context.Context, msg *SendVideoNote) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendVideoNote", msg)
+}
+
+// PostSendSticker will send a sticker and return the result from the server.
+func (b *Bot) PostSendSticker(msg *SendSticker) (*MessageResult, error) {
+	return b.PostSendStickerContext(context.Background(), msg)
+}
+
+// PostSendStickerContext is like PostSendSticker, but with a context.
+func (b *Bot) PostSendStickerContext(ctx context.Context, msg *SendSticker) (*MessageResult, error) {
+	return b.postMessage(ctx, "sendSticker", msg)
+}
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostSendPhoto(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":{"message_id":12345,"caption":"chart"}}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	result, err := b.PostSendPhoto(&SendPhoto{
+		ChatID:          1000,
+		MessageThreadID: 7,
+		Photo:           FromPath("test_publiccert.pem"),
+		Caption:         "chart",
+		CaptionEntities: []MessageEntity{{Type: "bold", Offset: 0, Length: 5}},
+		HasSpoiler:      true,
+		ReplyMarkup:     &ReplyMarkup{ForceReply: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), result.Result.ID)
+
+	req := transport.request
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/sendPhoto", req.URL.String())
+	assert.Equal(t, "1000", req.FormValue("chat_id"))
+	assert.Equal(t, "7", req.FormValue("message_thread_id"))
+	assert.Equal(t, "chart", req.FormValue("caption"))
+	assert.Equal(t, `[{"type":"bold","offset":0,"length":5}]`, req.FormValue("caption_entities"))
+	assert.Equal(t, "true", req.FormValue("has_spoiler"))
+	assert.Equal(t, `{"force_reply":true}`, req.FormValue("reply_markup"))
+	assert.Empty(t, req.FormValue("parse_mode"))
+
+	file, header, err := req.FormFile("photo")
+	assert.NoError(t, err)
+	contents, _ := ioutil.ReadAll(file)
+	assert.Equal(t, "publiccert\n", string(contents))
+	assert.Equal(t, "test_publiccert.pem", header.Filename)
+}
+
+func TestPostSendAudioThumbnail(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":{"message_id":12345}}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err := b.PostSendAudio(&SendAudio{
+		ChatID:    1000,
+		Audio:     FromReader("song.mp3", strings.NewReader("song")),
+		Thumbnail: FromBytes("cover.jpg", []byte("cover")),
+		Duration:  90,
+		Performer: "Band",
+	})
+	assert.NoError(t, err)
+
+	req := transport.request
+	assert.Equal(t, "90", req.FormValue("duration"))
+	assert.Equal(t, "Band", req.FormValue("performer"))
+	assert.Equal(t, "attach://thumbnail_file", req.FormValue("thumbnail"))
+
+	file, header, err := req.FormFile("audio")
+	assert.NoError(t, err)
+	contents, _ := ioutil.ReadAll(file)
+	assert.Equal(t, "song", string(contents))
+	assert.Equal(t, "song.mp3", header.Filename)
+
+	file, header, err = req.FormFile("thumbnail_file")
+	assert.NoError(t, err)
+	contents, _ = ioutil.ReadAll(file)
+	assert.Equal(t, "cover", string(contents))
+	assert.Equal(t, "cover.jpg", header.Filename)
+}
+
+func TestPostSendMediaByFileID(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":{"message_id":12345}}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err := b.PostSendVideo(&SendVideo{ChatID: 1000, Video: FromFileID("BAADBAADqwADBREAAR4BAAE"), SupportsStreaming: true})
+	assert.NoError(t, err)
+
+	req := transport.request
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	body, _ := ioutil.ReadAll(req.Body)
+	assert.Equal(t, `{"chat_id":1000,"video":"BAADBAADqwADBREAAR4BAAE","supports_streaming":true}`, strings.TrimSpace(string(body)))
+
+	_, err = b.PostSendAnimation(&SendAnimation{ChatID: 1000, Animation: FromURL("https://example.com/cat.gif")})
+	assert.NoError(t, err)
+
+	body, _ = ioutil.ReadAll(transport.request.Body)
+	assert.Equal(t, `{"chat_id":1000,"animation":"https://example.com/cat.gif"}`, strings.TrimSpace(string(body)))
+}
+
+func TestPostSendMediaErrors(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":{"message_id":12345}}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err := b.PostSendVoice(&SendVoice{ChatID: 1000})
+	assert.EqualError(t, err, "bot: voice not specified")
+
+	_, err = b.PostSendSticker(&SendSticker{ChatID: 1000, Sticker: FromPath("bad_file")})
+	assert.EqualError(t, err, "open bad_file: no such file or directory")
+	assert.Nil(t, transport.request)
+}
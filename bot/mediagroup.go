@@ -0,0 +1,236 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+)
+
+// Types of media in an album.
+const (
+	MediaTypePhoto    = "photo"
+	MediaTypeVideo    = "video"
+	MediaTypeAudio    = "audio"
+	MediaTypeDocument = "document"
+)
+
+// An album holds between MinMediaGroupSize and MaxMediaGroupSize items.
+const (
+	MinMediaGroupSize = 2
+	MaxMediaGroupSize = 10
+)
+
+// InputMedia represents a photo, video, audio file or document sent as part of an album.
+type InputMedia struct {
+	Type                        string          `json:"type"`
+	Media                       *InputFile      `json:"media"`
+	Thumbnail                   *InputFile      `json:"thumbnail,omitempty"`
+	Caption                     string          `json:"caption,omitempty"`
+	ParseMode                   string          `json:"parse_mode,omitempty"`
+	CaptionEntities             []MessageEntity `json:"caption_entities,omitempty"`
+	HasSpoiler                  bool            `json:"has_spoiler,omitempty"`        // photo and video
+	Width                       int             `json:"width,omitempty"`              // video
+	Height                      int             `json:"height,omitempty"`             // video
+	Duration                    int             `json:"duration,omitempty"`           // video and audio
+	SupportsStreaming           bool            `json:"supports_streaming,omitempty"` // video
+	Performer                   string          `json:"performer,omitempty"`          // audio
+	Title                       string          `json:"title,omitempty"`              // audio
+	DisableContentTypeDetection bool            `json:"disable_content_type_detection,omitempty"`
+}
+
+// SendMediaGroup represents the payload that needs to be sent to Telegram's sendMediaGroup method.
+// Uploads and files sent by file_id or URL can be mixed in one album.
+//
+// Example:
+//
+//	album := &bot.SendMediaGroup{ChatID: chatID}
+//	album.AddPhoto(bot.FromPath("front.jpg"), "Front").
+//		AddPhoto(bot.FromFileID(backID), "Back")
+//
+//	messages, err := b.PostSendMediaGroup(album)
+type SendMediaGroup struct {
+	ChatID              int64        `json:"chat_id"`
+	MessageThreadID     int64        `json:"message_thread_id,omitempty"`
+	Media               []InputMedia `json:"media"`
+	DisableNotification bool         `json:"disable_notification,omitempty"`
+	ProtectContent      bool         `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64        `json:"reply_to_message_id,omitempty"`
+}
+
+// MessagesResult represents the result of a call which sends several messages.
+type MessagesResult struct {
+	GenericResult
+	Result []Message `json:"result"`
+}
+
+// AddPhoto adds a photo to the album.
+func (m *SendMediaGroup) AddPhoto(file *InputFile, caption string) *SendMediaGroup {
+	return m.add(MediaTypePhoto, file, caption)
+}
+
+// AddVideo adds a video to the album.
+func (m *SendMediaGroup) AddVideo(file *InputFile, caption string) *SendMediaGroup {
+	return m.add(MediaTypeVideo, file, caption)
+}
+
+// AddAudio adds an audio file to the album.
+func (m *SendMediaGroup) AddAudio(file *InputFile, caption string) *SendMediaGroup {
+	return m.add(MediaTypeAudio, file, caption)
+}
+
+// AddDocument adds a document to the album.
+func (m *SendMediaGroup) AddDocument(file *InputFile, caption string) *SendMediaGroup {
+	return m.add(MediaTypeDocument, file, caption)
+}
+
+func (m *SendMediaGroup) add(mediaType string, file *InputFile, caption string) *SendMediaGroup {
+	m.Media = append(m.Media, InputMedia{Type: mediaType, Media: file, Caption: caption})
+	return m
+}
+
+// Validate checks the album against Telegram's rules: it must have 2 to 10 items, photos and
+// videos can be mixed, but audio files and documents can only be grouped with their own type.
+func (m *SendMediaGroup) Validate() error {
+	if len(m.Media) < MinMediaGroupSize || len(m.Media) > MaxMediaGroupSize {
+		return fmt.Errorf("bot: an album needs %d to %d items, not %d", MinMediaGroupSize, MaxMediaGroupSize, len(m.Media))
+	}
+
+	for i, item := range m.Media {
+		if item.Media == nil {
+			return fmt.Errorf("bot: media of album item %d not specified", i)
+		}
+
+		switch item.Type {
+		case MediaTypePhoto, MediaTypeVideo, MediaTypeAudio, MediaTypeDocument:
+		default:
+			return fmt.Errorf("bot: album item %d has invalid type %q", i, item.Type)
+		}
+
+		if mediaGroupKind(item.Type) != mediaGroupKind(m.Media[0].Type) {
+			return fmt.Errorf("bot: album can't mix %s and %s", m.Media[0].Type, item.Type)
+		}
+	}
+
+	return nil
+}
+
+// mediaGroupKind returns which types can be grouped with mediaType.
+func mediaGroupKind(mediaType string) string {
+	if mediaType == MediaTypeVideo {
+		return MediaTypePhoto
+	}
+
+	return mediaType
+}
+
+// attachedMedia is an InputMedia as encoded for Telegram, with uploads referenced as attach://.
+type attachedMedia struct {
+	*InputMedia
+	Media     string `json:"media"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// MarshalJSON encodes the album with each upload referenced by the form field it's sent in.
+func (m *SendMediaGroup) MarshalJSON() ([]byte, error) {
+	type sendMediaGroup SendMediaGroup
+
+	media := make([]attachedMedia, len(m.Media))
+	for i := range m.Media {
+		item := &m.Media[i]
+		media[i] = attachedMedia{
+			InputMedia: item,
+			Media:      attachReference(item.Media, mediaAttachment(i)),
+			Thumbnail:  attachReference(item.Thumbnail, thumbnailMediaAttachment(i)),
+		}
+	}
+
+	return json.Marshal(&struct {
+		*sendMediaGroup
+		Media []attachedMedia `json:"media"`
+	}{(*sendMediaGroup)(m), media})
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (m *SendMediaGroup) EncodeMultipart(w *multipart.Writer) error {
+	if err := writeFields(w, m); err != nil {
+		return err
+	}
+
+	for i, item := range m.Media {
+		if item.Media.IsUpload() {
+			if err := writeInputFile(w, mediaAttachment(i), item.Media); err != nil {
+				return err
+			}
+		}
+
+		if item.Thumbnail.IsUpload() {
+			if err := writeInputFile(w, thumbnailMediaAttachment(i), item.Thumbnail); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// files returns every file of the album, including thumbnails.
+func (m *SendMediaGroup) files() []*InputFile {
+	files := make([]*InputFile, 0, len(m.Media))
+	for _, item := range m.Media {
+		files = append(files, item.Media)
+		if item.Thumbnail != nil {
+			files = append(files, item.Thumbnail)
+		}
+	}
+
+	return files
+}
+
+func mediaAttachment(i int) string {
+	return fmt.Sprintf("file%d", i)
+}
+
+func thumbnailMediaAttachment(i int) string {
+	return fmt.Sprintf("thumbnail%d", i)
+}
+
+// attachReference returns how f is referenced in an album: by file_id, URL, or the form field
+// its upload is sent in.
+func attachReference(f *InputFile, attachment string) string {
+	switch {
+	case f == nil:
+		return ""
+	case f.fileID != "":
+		return f.fileID
+	case f.url != "":
+		return f.url
+	}
+
+	return "attach://" + attachment
+}
+
+// PostSendMediaGroup will send an album and return the messages it consists of.
+func (b *Bot) PostSendMediaGroup(msg *SendMediaGroup) ([]Message, error) {
+	return b.PostSendMediaGroupContext(context.Background(), msg)
+}
+
+// PostSendMediaGroupContext is like PostSendMediaGroup, but with a context. The album is
+// validated before anything is sent.
+func (b *Bot) PostSendMediaGroupContext(ctx context.Context, msg *SendMediaGroup) ([]Message, error) {
+	if msg == nil {
+		return nil, errors.New("bot: album not specified")
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result MessagesResult
+	if err := b.genericPost(ctx, "sendMediaGroup", msg, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
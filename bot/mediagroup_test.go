@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostSendMediaGroup(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":[{"message_id":1,"media_group_id":"g"},{"message_id":2,"media_group_id":"g"}]}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	album := &SendMediaGroup{ChatID: 1000}
+	album.AddPhoto(FromBytes("front.jpg", []byte("front")), "Front").
+		AddVideo(FromFileID("video-id"), "")
+	album.Media[1].Thumbnail = FromReader("thumb.jpg", strings.NewReader("thumb"))
+
+	messages, err := b.PostSendMediaGroup(album)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, int64(2), messages[1].ID)
+
+	req := transport.request
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/sendMediaGroup", req.URL.String())
+	assert.Equal(t, "1000", req.FormValue("chat_id"))
+	assert.JSONEq(t, `[
+		{"type":"photo","media":"attach://file0","caption":"Front"},
+		{"type":"video","media":"video-id","thumbnail":"attach://thumbnail1"}
+	]`, req.FormValue("media"))
+
+	file, header, err := req.FormFile("file0")
+	assert.NoError(t, err)
+	contents, _ := ioutil.ReadAll(file)
+	assert.Equal(t, "front", string(contents))
+	assert.Equal(t, "front.jpg", header.Filename)
+
+	file, _, err = req.FormFile("thumbnail1")
+	assert.NoError(t, err)
+	contents, _ = ioutil.ReadAll(file)
+	assert.Equal(t, "thumb", string(contents))
+}
+
+func TestPostSendMediaGroupFileIDs(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":[{"message_id":1},{"message_id":2}]}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	album := &SendMediaGroup{ChatID: 1000}
+	album.AddDocument(FromFileID("doc-1"), "").AddDocument(FromURL("https://example.com/report.pdf"), "Report")
+
+	_, err := b.PostSendMediaGroup(album)
+	assert.NoError(t, err)
+
+	req := transport.request
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	var body map[string]json.RawMessage
+	assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.JSONEq(t, `[
+		{"type":"document","media":"doc-1"},
+		{"type":"document","media":"https://example.com/report.pdf","caption":"Report"}
+	]`, string(body["media"]))
+}
+
+func TestSendMediaGroupValidate(t *testing.T) {
+	photo := FromFileID("photo")
+
+	tests := []struct {
+		name  string
+		media []InputMedia
+		err   string
+	}{
+		{"too few", []InputMedia{{Type: MediaTypePhoto, Media: photo}}, "bot: an album needs 2 to 10 items, not 1"},
+		{"too many", make([]InputMedia, 11), "bot: an album needs 2 to 10 items, not 11"},
+		{"photos and videos", []InputMedia{{Type: MediaTypePhoto, Media: photo}, {Type: MediaTypeVideo, Media: photo}}, ""},
+		{"documents", []InputMedia{{Type: MediaTypeDocument, Media: photo}, {Type: MediaTypeDocument, Media: photo}}, ""},
+		{"photo and document", []InputMedia{{Type: MediaTypePhoto, Media: photo}, {Type: MediaTypeDocument, Media: photo}}, "bot: album can't mix photo and document"},
+		{"audio and video", []InputMedia{{Type: MediaTypeAudio, Media: photo}, {Type: MediaTypeVideo, Media: photo}}, "bot: album can't mix audio and video"},
+		{"missing media", []InputMedia{{Type: MediaTypePhoto, Media: photo}, {Type: MediaTypePhoto}}, "bot: media of album item 1 not specified"},
+		{"invalid type", []InputMedia{{Type: MediaTypePhoto, Media: photo}, {Type: "sticker", Media: photo}}, `bot: album item 1 has invalid type "sticker"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := (&SendMediaGroup{ChatID: 1000, Media: test.media}).Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestPostSendMediaGroupInvalid(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":[]}`)
+
+	b := New("Test_Bot", "mysecrettoken", WithHTTPClient(&http.Client{Transport: transport}))
+
+	album := &SendMediaGroup{ChatID: 1000}
+	album.AddPhoto(FromFileID("photo"), "")
+
+	_, err := b.PostSendMediaGroup(album)
+	assert.Error(t, err)
+	assert.Nil(t, transport.request, "nothing is sent")
+}
@@ -0,0 +1,229 @@
+package bot
+
+// PhotoSize represents one size of a photo, or a thumbnail of a file or sticker.
+type PhotoSize struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+// Animation represents a GIF or an H.264/MPEG-4 AVC video without sound.
+type Animation struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+// Audio represents an audio file to be treated as music.
+type Audio struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Duration     int        `json:"duration"`
+	Performer    string     `json:"performer,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+	Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+}
+
+// Document represents a general file.
+type Document struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+// Video represents a video file.
+type Video struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+// VideoNote represents a round video message.
+type VideoNote struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Length       int        `json:"length"` // diameter of the video
+	Duration     int        `json:"duration"`
+	Thumbnail    *PhotoSize `json:"thumbnail,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+// Voice represents a voice note.
+type Voice struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+// Sticker represents a sticker.
+type Sticker struct {
+	FileID        string     `json:"file_id"`
+	FileUniqueID  string     `json:"file_unique_id"`
+	Type          string     `json:"type"` // regular, mask or custom_emoji
+	Width         int        `json:"width"`
+	Height        int        `json:"height"`
+	IsAnimated    bool       `json:"is_animated,omitempty"`
+	IsVideo       bool       `json:"is_video,omitempty"`
+	Thumbnail     *PhotoSize `json:"thumbnail,omitempty"`
+	Emoji         string     `json:"emoji,omitempty"`
+	SetName       string     `json:"set_name,omitempty"`
+	CustomEmojiID string     `json:"custom_emoji_id,omitempty"`
+	FileSize      int64      `json:"file_size,omitempty"`
+}
+
+// Contact represents a phone contact.
+type Contact struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	UserID      int64  `json:"user_id,omitempty"`
+	VCard       string `json:"vcard,omitempty"`
+}
+
+// Dice represents an animated emoji that displays a random value.
+type Dice struct {
+	Emoji string `json:"emoji"`
+	Value int    `json:"value"`
+}
+
+// Location represents a point on the map.
+type Location struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"` // in meters
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+// Venue represents a venue.
+type Venue struct {
+	Location        Location `json:"location"`
+	Title           string   `json:"title"`
+	Address         string   `json:"address"`
+	FoursquareID    string   `json:"foursquare_id,omitempty"`
+	FoursquareType  string   `json:"foursquare_type,omitempty"`
+	GooglePlaceID   string   `json:"google_place_id,omitempty"`
+	GooglePlaceType string   `json:"google_place_type,omitempty"`
+}
+
+// Types of MessageOrigin.
+const (
+	MessageOriginUser       = "user"
+	MessageOriginHiddenUser = "hidden_user"
+	MessageOriginChat       = "chat"
+	MessageOriginChannel    = "channel"
+)
+
+// MessageOrigin describes where a forwarded message originally came from. Which fields are set
+// depends on Type.
+type MessageOrigin struct {
+	Type            string `json:"type"`
+	Date            int    `json:"date"`
+	SenderUser      *User  `json:"sender_user,omitempty"`      // user
+	SenderUserName  string `json:"sender_user_name,omitempty"` // hidden_user
+	SenderChat      *Chat  `json:"sender_chat,omitempty"`      // chat
+	Chat            *Chat  `json:"chat,omitempty"`             // channel
+	MessageID       int64  `json:"message_id,omitempty"`       // channel
+	AuthorSignature string `json:"author_signature,omitempty"` // chat and channel
+}
+
+// MessageAutoDeleteTimerChanged represents a service message about a change in auto-delete timer
+// settings.
+type MessageAutoDeleteTimerChanged struct {
+	MessageAutoDeleteTime int `json:"message_auto_delete_time"`
+}
+
+// ForumTopicCreated represents a service message about a new forum topic.
+type ForumTopicCreated struct {
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// ForumTopicEdited represents a service message about an edited forum topic.
+type ForumTopicEdited struct {
+	Name              string  `json:"name,omitempty"`
+	IconCustomEmojiID *string `json:"icon_custom_emoji_id,omitempty"` // empty if the icon was removed
+}
+
+// ForumTopicClosed represents a service message about a forum topic closed in the chat.
+type ForumTopicClosed struct{}
+
+// ForumTopicReopened represents a service message about a forum topic reopened in the chat.
+type ForumTopicReopened struct{}
+
+// VideoChatScheduled represents a service message about a video chat scheduled in the chat.
+type VideoChatScheduled struct {
+	StartDate int `json:"start_date"`
+}
+
+// VideoChatStarted represents a service message about a video chat started in the chat.
+type VideoChatStarted struct{}
+
+// VideoChatEnded represents a service message about a video chat ended in the chat.
+type VideoChatEnded struct {
+	Duration int `json:"duration"` // in seconds
+}
+
+// VideoChatParticipantsInvited represents a service message about new members invited to a
+// video chat.
+type VideoChatParticipantsInvited struct {
+	Users []User `json:"users"`
+}
+
+// LargestPhoto returns the largest size of the message's photo, or nil if it has none.
+func (m *Message) LargestPhoto() *PhotoSize {
+	if len(m.Photo) == 0 {
+		return nil
+	}
+
+	return &m.Photo[len(m.Photo)-1]
+}
+
+// IsServiceMessage returns true if the message reports an event in the chat, such as new
+// members or a pinned message, rather than content sent by a user.
+func (m *Message) IsServiceMessage() bool {
+	return len(m.NewChatMembers) > 0 ||
+		m.LeftChatMember != nil ||
+		m.NewChatTitle != "" ||
+		len(m.NewChatPhoto) > 0 ||
+		m.DeleteChatPhoto ||
+		m.GroupChatCreated ||
+		m.SupergroupChatCreated ||
+		m.ChannelChatCreated ||
+		m.MessageAutoDeleteTimerChanged != nil ||
+		m.MigrateToChatID != 0 ||
+		m.MigrateFromChatID != 0 ||
+		m.PinnedMessage != nil ||
+		m.ForumTopicCreated != nil ||
+		m.ForumTopicEdited != nil ||
+		m.ForumTopicClosed != nil ||
+		m.ForumTopicReopened != nil ||
+		m.VideoChatScheduled != nil ||
+		m.VideoChatStarted != nil ||
+		m.VideoChatEnded != nil ||
+		m.VideoChatParticipantsInvited != nil
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeMediaMessage(t *testing.T) {
+	jsonStr := `
+{
+    "message_id": 4105,
+    "message_thread_id": 17,
+    "sender_chat": {"id": -1001234, "type": "channel", "title": "News"},
+    "date": 1449369855,
+    "chat": {"id": -1005678, "type": "supergroup", "title": "Discussion"},
+    "is_topic_message": true,
+    "via_bot": {"id": 141317493, "first_name": "Inline Bot", "username": "Inline_Bot", "is_bot": true},
+    "media_group_id": "13579",
+    "photo": [
+        {"file_id": "small", "file_unique_id": "s", "width": 90, "height": 60, "file_size": 1024},
+        {"file_id": "large", "file_unique_id": "l", "width": 1280, "height": 853}
+    ],
+    "caption": "Look at this",
+    "caption_entities": [{"type": "bold", "offset": 0, "length": 4}],
+    "has_media_spoiler": true
+}`
+
+	var m Message
+	assert.NoError(t, json.Unmarshal([]byte(jsonStr), &m))
+
+	assert.Equal(t, int64(17), m.MessageThreadID)
+	assert.Equal(t, "News", m.SenderChat.Title)
+	assert.True(t, m.IsTopicMessage)
+	assert.Equal(t, "Inline_Bot", m.ViaBot.Username)
+	assert.Equal(t, "13579", m.MediaGroupID)
+
+	assert.Len(t, m.Photo, 2)
+	assert.Equal(t, int64(1024), m.Photo[0].FileSize)
+	assert.Equal(t, "large", m.LargestPhoto().FileID)
+	assert.Equal(t, "Look at this", m.Caption)
+	assert.Equal(t, []MessageEntity{{Type: "bold", Offset: 0, Length: 4}}, m.CaptionEntities)
+	assert.True(t, m.HasMediaSpoiler)
+	assert.False(t, m.IsServiceMessage())
+}
+
+func TestDecodeDocumentMessage(t *testing.T) {
+	jsonStr := `
+{
+    "message_id": 4106,
+    "date": 1449369855,
+    "chat": {"id": 144255044, "type": "private"},
+    "document": {
+        "file_id": "doc",
+        "file_unique_id": "d",
+        "thumbnail": {"file_id": "thumb", "file_unique_id": "t", "width": 90, "height": 90},
+        "file_name": "report.pdf",
+        "mime_type": "application/pdf",
+        "file_size": 52428800
+    },
+    "forward_origin": {"type": "hidden_user", "date": 1449369000, "sender_user_name": "Jane"}
+}`
+
+	var m Message
+	assert.NoError(t, json.Unmarshal([]byte(jsonStr), &m))
+
+	assert.Equal(t, "report.pdf", m.Document.FileName)
+	assert.Equal(t, "application/pdf", m.Document.MimeType)
+	assert.Equal(t, int64(52428800), m.Document.FileSize)
+	assert.Equal(t, "thumb", m.Document.Thumbnail.FileID)
+	assert.Nil(t, m.LargestPhoto())
+
+	assert.Equal(t, MessageOriginHiddenUser, m.ForwardOrigin.Type)
+	assert.Equal(t, "Jane", m.ForwardOrigin.SenderUserName)
+}
+
+func TestDecodeServiceMessages(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		check func(t *testing.T, m *Message)
+	}{
+		{"new_chat_members", `{"new_chat_members":[{"id":1,"first_name":"John"},{"id":2,"first_name":"Jane"}]}`, func(t *testing.T, m *Message) {
+			assert.Len(t, m.NewChatMembers, 2)
+			assert.Equal(t, "Jane", m.NewChatMembers[1].FirstName)
+		}},
+		{"left_chat_member", `{"left_chat_member":{"id":1,"first_name":"John"}}`, func(t *testing.T, m *Message) {
+			assert.Equal(t, int64(1), m.LeftChatMember.ID)
+		}},
+		{"pinned_message", `{"pinned_message":{"message_id":10,"date":1449369000,"chat":{"id":2},"text":"rules"}}`, func(t *testing.T, m *Message) {
+			assert.Equal(t, "rules", m.PinnedMessage.Text)
+		}},
+		{"video_chat_started", `{"video_chat_started":{}}`, func(t *testing.T, m *Message) {
+			assert.NotNil(t, m.VideoChatStarted)
+		}},
+		{"video_chat_ended", `{"video_chat_ended":{"duration":3600}}`, func(t *testing.T, m *Message) {
+			assert.Equal(t, 3600, m.VideoChatEnded.Duration)
+		}},
+		{"video_chat_participants_invited", `{"video_chat_participants_invited":{"users":[{"id":3,"first_name":"Jim"}]}}`, func(t *testing.T, m *Message) {
+			assert.Equal(t, "Jim", m.VideoChatParticipantsInvited.Users[0].FirstName)
+		}},
+		{"forum_topic_created", `{"forum_topic_created":{"name":"Support","icon_color":7322096}}`, func(t *testing.T, m *Message) {
+			assert.Equal(t, "Support", m.ForumTopicCreated.Name)
+		}},
+		{"message_auto_delete_timer_changed", `{"message_auto_delete_timer_changed":{"message_auto_delete_time":86400}}`, func(t *testing.T, m *Message) {
+			assert.Equal(t, 86400, m.MessageAutoDeleteTimerChanged.MessageAutoDeleteTime)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var m Message
+			assert.NoError(t, json.Unmarshal([]byte(test.json), &m))
+			assert.True(t, m.IsServiceMessage())
+			test.check(t, &m)
+		})
+	}
+}
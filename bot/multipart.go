@@ -0,0 +1,270 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// MultipartEncoder is implemented by payloads which must be sent as multipart/form-data,
+// usually because they upload a file.
+type MultipartEncoder interface {
+	// EncodeMultipart should write every field of the payload to w. w is closed by the caller.
+	EncodeMultipart(w *multipart.Writer) error
+}
+
+// multipartPost streams the payload to Telegram through a pipe, so uploads are never held in
+// memory as a whole.
+func (b *Bot) multipartPost(ctx context.Context, endpoint string, msg MultipartEncoder, result apiResult) error {
+	chatID, n := throttleTarget(msg)
+
+	readers := newUploadReaders(msg)
+	return b.do(ctx, endpoint, func() (*http.Request, error) {
+		if err := readers.rewind(); err != nil {
+			return nil, err
+		}
+
+		if err := b.throttle(ctx, chatID, n); err != nil {
+			return nil, err
+		}
+
+		body, contentType := newUploadBody(msg)
+
+		r, err := http.NewRequestWithContext(ctx, "POST", b.URL(endpoint), body)
+		if err != nil {
+			body.Close()
+			body.finish()
+			return nil, err
+		}
+
+		r.Header.Set("Content-Type", contentType)
+		return r, nil
+	}, result)
+}
+
+// uploadBody is a request body which is encoded while it is being sent.
+type uploadBody struct {
+	*io.PipeReader
+	done chan struct{}
+	err  error
+}
+
+func newUploadBody(msg MultipartEncoder) (*uploadBody, string) {
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+
+	u := &uploadBody{
+		PipeReader: pr,
+		done:       make(chan struct{}),
+	}
+
+	go func() {
+		defer close(u.done)
+
+		err := msg.EncodeMultipart(w)
+		if err == nil {
+			err = w.Close()
+		}
+
+		u.err = err
+		pw.CloseWithError(err)
+	}()
+
+	return u, w.FormDataContentType()
+}
+
+// finish waits for the encoder to stop and returns its error. Writes that fail because the
+// request ended early are not reported.
+func (u *uploadBody) finish() error {
+	u.PipeReader.Close()
+	<-u.done
+
+	if errors.Is(u.err, io.ErrClosedPipe) {
+		return nil
+	}
+
+	return u.err
+}
+
+// thumbnailAttachment is the form field a thumbnail is uploaded under.
+const thumbnailAttachment = "thumbnail_file"
+
+// needsMultipart returns false for media payloads which only reference files by file_id or URL,
+// since those can be sent as JSON.
+func needsMultipart(msg MultipartEncoder) bool {
+	switch m := msg.(type) {
+	case mediaPayload:
+		_, file, thumbnail := m.media()
+		return file == nil || file.IsUpload() || thumbnail.IsUpload()
+	case *SendMediaGroup:
+		for _, f := range m.files() {
+			if f.IsUpload() {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// uploadReaders are the readers a payload uploads from. Where each of them started is kept
+// with the request rather than in the InputFile, so they can be rewound for a retry.
+type uploadReaders struct {
+	readers []io.Reader
+	offsets []int64
+	started bool
+}
+
+func newUploadReaders(msg MultipartEncoder) *uploadReaders {
+	var files []*InputFile
+	switch m := msg.(type) {
+	case mediaPayload:
+		_, file, thumbnail := m.media()
+		files = []*InputFile{file, thumbnail}
+	case *SendMediaGroup:
+		files = m.files()
+	}
+
+	u := &uploadReaders{}
+	for _, f := range files {
+		if f.IsUpload() && f.reader != nil {
+			u.readers = append(u.readers, f.reader)
+		}
+	}
+
+	return u
+}
+
+// rewind records where the readers start before the first attempt, and seeks back there before
+// any later one. It returns errNotReplayable if a reader was consumed and can't seek.
+func (u *uploadReaders) rewind() error {
+	if !u.started {
+		u.started = true
+		u.offsets = make([]int64, len(u.readers))
+
+		for i, r := range u.readers {
+			if seeker, ok := r.(io.Seeker); ok {
+				offset, err := seeker.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return err
+				}
+				u.offsets[i] = offset
+			}
+		}
+
+		return nil
+	}
+
+	for i, r := range u.readers {
+		seeker, ok := r.(io.Seeker)
+		if !ok {
+			return errNotReplayable
+		}
+
+		if _, err := seeker.Seek(u.offsets[i], io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeMedia writes every field of msg, followed by the media file and thumbnail if they are
+// uploaded. It is the encoder shared by all media payloads.
+func encodeMedia(w *multipart.Writer, msg mediaPayload) error {
+	field, file, thumbnail := msg.media()
+	if file == nil {
+		return fmt.Errorf("bot: %s not specified", field)
+	}
+
+	if err := writeFields(w, msg); err != nil {
+		return err
+	}
+
+	if file.IsUpload() {
+		if err := writeInputFile(w, field, file); err != nil {
+			return err
+		}
+	}
+
+	if thumbnail.IsUpload() {
+		w.WriteField("thumbnail", "attach://"+thumbnailAttachment)
+		return writeInputFile(w, thumbnailAttachment, thumbnail)
+	}
+
+	return nil
+}
+
+// writeFields writes every field msg has when encoded as JSON. Strings are written as is, while
+// numbers, booleans, arrays and objects are written as JSON. Null fields are skipped.
+func writeFields(w *multipart.Writer, msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		raw := fields[name]
+		if string(raw) == "null" {
+			continue
+		}
+
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+		}
+
+		if err := w.WriteField(name, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFile copies the file at path into a form file field.
+func writeFile(w *multipart.Writer, field, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	part, err := w.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, file)
+	return err
+}
+
+// writeJSONField writes v as a JSON encoded form field.
+func writeJSONField(w *multipart.Writer, field string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteField(field, string(b))
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100000)
+
+	var received []byte
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, int64(-1), r.ContentLength, "body is streamed")
+
+		file, _, err := r.FormFile("document")
+		assert.NoError(t, err)
+		received, _ = ioutil.ReadAll(file)
+
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	})
+
+	var progress []int64
+	err := b.PostSendDocument(&SendDocument{
+		ChatID: 1000,
+		Document: FromReader("big.bin", bytes.NewReader(content)).WithProgress(func(written int64) {
+			progress = append(progress, written)
+		}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, content, received)
+
+	assert.NotEmpty(t, progress)
+	assert.Equal(t, int64(len(content)), progress[len(progress)-1])
+}
+
+func TestStreamingUploadRetry(t *testing.T) {
+	calls := 0
+	var received []string
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		file, _, err := r.FormFile("voice")
+		assert.NoError(t, err)
+		contents, _ := ioutil.ReadAll(file)
+		received = append(received, string(contents))
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 0"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	})
+	b.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseDelay: 1})
+
+	// a seekable reader is rewound for the second attempt
+	_, err := b.PostSendVoice(&SendVoice{ChatID: 1000, Voice: FromReader("voice.ogg", strings.NewReader("voice"))})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"voice", "voice"}, received)
+
+	// a reader which can't seek isn't retried, and the original error is returned
+	calls = 0
+	_, err = b.PostSendVoice(&SendVoice{ChatID: 1000, Voice: FromReader("voice.ogg", io.MultiReader(strings.NewReader("voice")))})
+	assert.True(t, IsFloodWait(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestConcurrentUploadsOfSameFile(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]string)
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("photo")
+		assert.NoError(t, err)
+		contents, _ := ioutil.ReadAll(file)
+
+		mu.Lock()
+		received[r.FormValue("chat_id")] = string(contents)
+		mu.Unlock()
+
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	})
+
+	// sending an InputFile doesn't change it, so the same one can go to several chats at once
+	photo := FromBytes("chart.png", []byte("chart"))
+
+	var wg sync.WaitGroup
+	for chatID := int64(1); chatID <= 5; chatID++ {
+		wg.Add(1)
+		go func(chatID int64) {
+			defer wg.Done()
+			_, err := b.PostSendPhoto(&SendPhoto{ChatID: chatID, Photo: photo})
+			assert.NoError(t, err)
+		}(chatID)
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]string{"1": "chart", "2": "chart", "3": "chart", "4": "chart", "5": "chart"}, received)
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBaseURL is the address of Telegram's hosted Bot API server.
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Option configures a Bot created with New.
+type Option func(*Bot)
+
+// WithBaseURL sets the address of the Bot API server, e.g. "http://localhost:8081" for a
+// self-hosted telegram-bot-api server or a fake server in tests. Files are downloaded from the
+// same address unless WithFileBaseURL is used.
+func WithBaseURL(baseURL string) Option {
+	return func(b *Bot) {
+		b.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithFileBaseURL sets the address files are downloaded from.
+func WithFileBaseURL(fileBaseURL string) Option {
+	return func(b *Bot) {
+		b.fileBaseURL = strings.TrimRight(fileBaseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the client used for every request to the Bot API.
+func WithHTTPClient(client *http.Client) Option {
+	return func(b *Bot) {
+		b.client = client
+	}
+}
+
+// WithTestEnvironment sends every request to Telegram's test environment.
+func WithTestEnvironment() Option {
+	return func(b *Bot) {
+		b.testEnvironment = true
+	}
+}
+
+// WithLocalServer is for a telegram-bot-api server running with --local on the same machine.
+// Such a server returns absolute paths from getFile, which are read straight from disk.
+func WithLocalServer() Option {
+	return func(b *Bot) {
+		b.localServer = true
+	}
+}
+
+func (b *Bot) environment() string {
+	if b.testEnvironment {
+		return "/test"
+	}
+
+	return ""
+}
+
+// isLocalFile returns true if filePath is on this machine's disk.
+func (b *Bot) isLocalFile(filePath string) bool {
+	return b.localServer && filepath.IsAbs(filePath)
+}
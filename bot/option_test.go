@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/getMe", b.URL("getMe"))
+	assert.Equal(t, "https://api.telegram.org/file/botmysecrettoken/documents/file_1.pdf", b.FileURL("documents/file_1.pdf"))
+	assert.Equal(t, http.DefaultClient, b.client)
+
+	client := &http.Client{}
+	b = New("Test_Bot", "mysecrettoken", WithBaseURL("http://localhost:8081/"), WithHTTPClient(client))
+	assert.Equal(t, "http://localhost:8081/botmysecrettoken/getMe", b.URL("getMe"))
+	assert.Equal(t, "http://localhost:8081/file/botmysecrettoken/documents/file_1.pdf", b.FileURL("documents/file_1.pdf"))
+	assert.Equal(t, client, b.client)
+
+	b = New("Test_Bot", "mysecrettoken", WithFileBaseURL("https://files.example.com"), WithTestEnvironment())
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/test/getMe", b.URL("getMe"))
+	assert.Equal(t, "https://files.example.com/file/botmysecrettoken/test/documents/file_1.pdf", b.FileURL("documents/file_1.pdf"))
+}
+
+func TestWithLocalServer(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken", WithBaseURL("http://localhost:8081"), WithLocalServer())
+	assert.Equal(t, "file:///var/lib/telegram-bot-api/mysecrettoken/documents/file_1.pdf", b.FileURL("/var/lib/telegram-bot-api/mysecrettoken/documents/file_1.pdf"))
+	assert.Equal(t, "http://localhost:8081/file/botmysecrettoken/documents/file_1.pdf", b.FileURL("documents/file_1.pdf"))
+}
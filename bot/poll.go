@@ -0,0 +1,231 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Types of Poll.
+const (
+	PollTypeRegular = "regular"
+	PollTypeQuiz    = "quiz"
+)
+
+// Limits Telegram enforces on polls.
+const (
+	MinPollOptions    = 2
+	MaxPollOptions    = 10
+	MinPollOpenPeriod = 5   // seconds
+	MaxPollOpenPeriod = 600 // seconds
+)
+
+// Poll represents a poll or quiz.
+type Poll struct {
+	ID                    string          `json:"id"`
+	Question              string          `json:"question"`
+	Options               []PollOption    `json:"options"`
+	TotalVoterCount       int             `json:"total_voter_count"`
+	IsClosed              bool            `json:"is_closed"`
+	IsAnonymous           bool            `json:"is_anonymous"`
+	Type                  string          `json:"type"` // regular or quiz
+	AllowsMultipleAnswers bool            `json:"allows_multiple_answers"`
+	CorrectOptionID       *int            `json:"correct_option_id,omitempty"` // only known for quizzes the bot sent
+	Explanation           string          `json:"explanation,omitempty"`
+	ExplanationEntities   []MessageEntity `json:"explanation_entities,omitempty"`
+	OpenPeriod            int             `json:"open_period,omitempty"`
+	CloseDate             int             `json:"close_date,omitempty"`
+}
+
+// PollOption represents one answer option of a poll and how many users chose it.
+type PollOption struct {
+	Text       string `json:"text"`
+	VoterCount int    `json:"voter_count"`
+}
+
+// PollAnswer represents a user's vote in a non-anonymous poll. OptionIDs is empty if the user
+// retracted their vote.
+type PollAnswer struct {
+	PollID    string `json:"poll_id"`
+	VoterChat *Chat  `json:"voter_chat,omitempty"` // set if an anonymous chat admin voted
+	User      *User  `json:"user,omitempty"`
+	OptionIDs []int  `json:"option_ids"`
+}
+
+// VoterID returns the ID of the user who voted, or of the chat if the vote was cast on behalf of
+// a chat.
+func (a *PollAnswer) VoterID() int64 {
+	if a.User != nil {
+		return a.User.ID
+	}
+
+	if a.VoterChat != nil {
+		return a.VoterChat.ID
+	}
+
+	return 0
+}
+
+// SendPoll represents the payload that needs to be sent to Telegram's sendPoll method.
+type SendPoll struct {
+	ChatID          int64    `json:"chat_id"`
+	MessageThreadID int64    `json:"message_thread_id,omitempty"`
+	Question        string   `json:"question"`
+	Options         []string `json:"options"`
+
+	// IsAnonymous defaults to true if nil.
+	IsAnonymous *bool  `json:"is_anonymous,omitempty"`
+	Type        string `json:"type,omitempty"` // regular or quiz, defaults to regular
+
+	AllowsMultipleAnswers bool `json:"allows_multiple_answers,omitempty"`
+
+	// CorrectOptionID is the index of the right answer of a quiz, and required for quizzes.
+	CorrectOptionID *int `json:"correct_option_id,omitempty"`
+
+	// Explanation is shown when a user chooses a wrong answer of a quiz.
+	Explanation          string          `json:"explanation,omitempty"`
+	ExplanationParseMode string          `json:"explanation_parse_mode,omitempty"`
+	ExplanationEntities  []MessageEntity `json:"explanation_entities,omitempty"`
+
+	// OpenPeriod is how many seconds the poll is open for. It can't be combined with CloseDate.
+	OpenPeriod int  `json:"open_period,omitempty"`
+	CloseDate  int  `json:"close_date,omitempty"`
+	IsClosed   bool `json:"is_closed,omitempty"`
+
+	DisableNotification bool         `json:"disable_notification,omitempty"`
+	ProtectContent      bool         `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64        `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup `json:"reply_markup,omitempty"`
+}
+
+// StopPoll represents the payload that needs to be sent to Telegram's stopPoll method.
+type StopPoll struct {
+	ChatID      int64        `json:"chat_id"`
+	MessageID   int64        `json:"message_id"`
+	ReplyMarkup *ReplyMarkup `json:"reply_markup,omitempty"`
+}
+
+// PollResult represents the result of a stopPoll call.
+type PollResult struct {
+	GenericResult
+	Result *Poll `json:"result"`
+}
+
+// NewQuiz returns a quiz for chatID where options[correct] is the right answer.
+func NewQuiz(chatID int64, question string, options []string, correct int) *SendPoll {
+	return &SendPoll{
+		ChatID:          chatID,
+		Question:        question,
+		Options:         options,
+		Type:            PollTypeQuiz,
+		CorrectOptionID: &correct,
+	}
+}
+
+// Validate checks the poll against Telegram's rules: it must have 2 to 10 options, a quiz needs
+// a correct option and can't allow multiple answers, and only one of OpenPeriod and CloseDate
+// may be set.
+func (m *SendPoll) Validate() error {
+	if m.Question == "" {
+		return errors.New("bot: poll question not specified")
+	}
+
+	if len(m.Options) < MinPollOptions || len(m.Options) > MaxPollOptions {
+		return fmt.Errorf("bot: a poll needs %d to %d options, not %d", MinPollOptions, MaxPollOptions, len(m.Options))
+	}
+
+	switch m.Type {
+	case "", PollTypeRegular:
+		if m.CorrectOptionID != nil || m.Explanation != "" {
+			return errors.New("bot: only quizzes have a correct option and an explanation")
+		}
+	case PollTypeQuiz:
+		if m.CorrectOptionID == nil || *m.CorrectOptionID < 0 || *m.CorrectOptionID >= len(m.Options) {
+			return errors.New("bot: quiz needs the index of its correct option")
+		}
+
+		if m.AllowsMultipleAnswers {
+			return errors.New("bot: quiz can't allow multiple answers")
+		}
+	default:
+		return fmt.Errorf("bot: invalid poll type %q", m.Type)
+	}
+
+	if m.OpenPeriod != 0 && (m.OpenPeriod < MinPollOpenPeriod || m.OpenPeriod > MaxPollOpenPeriod) {
+		return fmt.Errorf("bot: poll open period must be %d to %d seconds", MinPollOpenPeriod, MaxPollOpenPeriod)
+	}
+
+	if m.OpenPeriod != 0 && m.CloseDate != 0 {
+		return errors.New("bot: poll can't have both an open period and a close date")
+	}
+
+	return nil
+}
+
+// SetPollHandler will register a handler to be called when the state of a poll changed, e.g. it
+// got a new vote or was closed. Telegram only sends these for polls the bot sent. args is the ID of
+// the poll.
+func (b *Bot) SetPollHandler(h Handler) {
+	b.PollHandler = h
+}
+
+// SetPollAnswerHandler will register a handler to be called when a user voted in a non-anonymous
+// poll the bot sent. args is the ID of the poll.
+func (b *Bot) SetPollAnswerHandler(h Handler) {
+	b.PollAnswerHandler = h
+}
+
+// PostSendPoll will send a poll or quiz and return the result from the server. The poll is
+// validated first.
+func (b *Bot) PostSendPoll(msg *SendPoll) (*MessageResult, error) {
+	return b.PostSendPollContext(context.Background(), msg)
+}
+
+// PostSendPollContext is like PostSendPoll, but with a context.
+func (b *Bot) PostSendPollContext(ctx context.Context, msg *SendPoll) (*MessageResult, error) {
+	if msg == nil {
+		return nil, errors.New("bot: poll not specified")
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return b.postMessage(ctx, "sendPoll", msg)
+}
+
+// PostStopPoll will close a poll the bot sent and return its final results.
+func (b *Bot) PostStopPoll(msg *StopPoll) (*Poll, error) {
+	return b.PostStopPollContext(context.Background(), msg)
+}
+
+// PostStopPollContext is like PostStopPoll, but with a context.
+func (b *Bot) PostStopPollContext(ctx context.Context, msg *StopPoll) (*Poll, error) {
+	var result PollResult
+	if err := b.genericPost(ctx, "stopPoll", msg, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
+
+// dispatchPoll passes poll updates and poll answers to their handlers.
+func (b *Bot) dispatchPoll(ctx context.Context, ur *UpdateResponse) error {
+	if b.Debug {
+		log.Printf("received in %s: %s\n", b.BotName, ur.String())
+	}
+
+	switch {
+	case ur.Poll != nil:
+		if h := b.PollHandler; h != nil {
+			h(ctx, b, ur, ur.Poll.ID)
+		}
+	case ur.PollAnswer != nil:
+		if h := b.PollAnswerHandler; h != nil {
+			h(ctx, b, ur, ur.PollAnswer.PollID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendPollValidate(t *testing.T) {
+	options := []string{"Yes", "No"}
+	multiple := &SendPoll{Question: "?", Options: options, Type: PollTypeQuiz, CorrectOptionID: new(int), AllowsMultipleAnswers: true}
+
+	tests := []struct {
+		name string
+		poll *SendPoll
+		err  string
+	}{
+		{"regular", &SendPoll{Question: "Lunch?", Options: options, OpenPeriod: 60}, ""},
+		{"quiz", NewQuiz(1, "2+2?", []string{"3", "4"}, 1), ""},
+		{"no question", &SendPoll{Options: options}, "bot: poll question not specified"},
+		{"one option", &SendPoll{Question: "?", Options: options[:1]}, "bot: a poll needs 2 to 10 options, not 1"},
+		{"correct option of regular poll", &SendPoll{Question: "?", Options: options, CorrectOptionID: new(int)}, "bot: only quizzes have a correct option and an explanation"},
+		{"quiz without correct option", &SendPoll{Question: "?", Options: options, Type: PollTypeQuiz}, "bot: quiz needs the index of its correct option"},
+		{"correct option out of range", NewQuiz(1, "?", options, 2), "bot: quiz needs the index of its correct option"},
+		{"quiz with multiple answers", multiple, "bot: quiz can't allow multiple answers"},
+		{"invalid type", &SendPoll{Question: "?", Options: options, Type: "vote"}, `bot: invalid poll type "vote"`},
+		{"open period too long", &SendPoll{Question: "?", Options: options, OpenPeriod: 601}, "bot: poll open period must be 5 to 600 seconds"},
+		{"open period and close date", &SendPoll{Question: "?", Options: options, OpenPeriod: 60, CloseDate: 1700000000}, "bot: poll can't have both an open period and a close date"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.poll.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestPostSendPollAndStopPoll(t *testing.T) {
+	var bodies []string
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies = append(bodies, string(data))
+
+		switch r.URL.Path {
+		case "/botmysecrettoken/sendPoll":
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":5,"date":1,"chat":{"id":1},"poll":{"id":"p1","question":"2+2?","options":[{"text":"3","voter_count":0},{"text":"4","voter_count":0}],"type":"quiz","correct_option_id":1}}}`)
+		case "/botmysecrettoken/stopPoll":
+			fmt.Fprint(w, `{"ok":true,"result":{"id":"p1","question":"2+2?","options":[{"text":"3","voter_count":1},{"text":"4","voter_count":2}],"total_voter_count":3,"is_closed":true,"type":"quiz","correct_option_id":1}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	quiz := NewQuiz(1, "2+2?", []string{"3", "4"}, 1)
+	quiz.IsAnonymous = new(bool)
+	quiz.Explanation = "Count again"
+	quiz.OpenPeriod = 30
+
+	result, err := b.PostSendPoll(quiz)
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", result.Result.Poll.ID)
+	assert.Equal(t, 1, *result.Result.Poll.CorrectOptionID)
+
+	poll, err := b.PostStopPoll(&StopPoll{ChatID: 1, MessageID: 5})
+	assert.NoError(t, err)
+	assert.True(t, poll.IsClosed)
+	assert.Equal(t, 2, poll.Options[1].VoterCount)
+
+	// invalid polls are never sent
+	_, err = b.PostSendPoll(&SendPoll{ChatID: 1, Question: "?"})
+	assert.Error(t, err)
+
+	assert.Len(t, bodies, 2)
+	assert.JSONEq(t, `{
+		"chat_id": 1,
+		"question": "2+2?",
+		"options": ["3", "4"],
+		"is_anonymous": false,
+		"type": "quiz",
+		"correct_option_id": 1,
+		"explanation": "Count again",
+		"open_period": 30
+	}`, bodies[0])
+	assert.JSONEq(t, `{"chat_id": 1, "message_id": 5}`, bodies[1])
+}
+
+func TestPollTally(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	tally := NewPollTally()
+	b.SetPollAnswerHandler(tally.HandlePollAnswer)
+
+	var polls []string
+	b.SetPollHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		polls = append(polls, args)
+	})
+
+	const updates = `[
+		{"update_id": 1, "poll_answer": {"poll_id": "p1", "user": {"id": 1, "first_name": "John"}, "option_ids": [0]}},
+		{"update_id": 2, "poll_answer": {"poll_id": "p1", "user": {"id": 2, "first_name": "Jane"}, "option_ids": [0, 2]}},
+		{"update_id": 3, "poll_answer": {"poll_id": "p1", "voter_chat": {"id": -100, "type": "supergroup"}, "option_ids": [1]}},
+		{"update_id": 4, "poll_answer": {"poll_id": "p1", "user": {"id": 1, "first_name": "John"}, "option_ids": [2]}},
+		{"update_id": 5, "poll_answer": {"poll_id": "p2", "user": {"id": 1, "first_name": "John"}, "option_ids": [1]}},
+		{"update_id": 6, "poll_answer": {"poll_id": "p2", "user": {"id": 1, "first_name": "John"}, "option_ids": []}},
+		{"update_id": 7, "poll": {"id": "p1", "question": "Lunch?", "options": [], "total_voter_count": 3, "is_closed": true, "type": "regular"}}
+	]`
+
+	var urs []UpdateResponse
+	assert.NoError(t, json.Unmarshal([]byte(updates), &urs))
+
+	for i := range urs {
+		assert.NoError(t, b.Dispatch(context.Background(), &urs[i]))
+	}
+
+	assert.Equal(t, int64(-100), urs[2].FromID())
+	assert.Equal(t, []string{"p1"}, polls)
+
+	assert.Equal(t, map[int64][]int{1: {2}, 2: {0, 2}, -100: {1}}, tally.Votes("p1"))
+	assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 2}, tally.Counts("p1"))
+	assert.Equal(t, []int64{1, 2}, tally.Voters("p1", 2))
+	assert.Empty(t, tally.Votes("p2"))
+
+	tally.Delete("p1")
+	assert.Empty(t, tally.Counts("p1"))
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultPollTimeout is the long polling timeout used by StartPolling when Bot.PollTimeout is zero.
+const DefaultPollTimeout = 30 * time.Second
+
+// backoff bounds used when getUpdates fails. Variables so tests can shorten them.
+var (
+	pollBackoffMin = time.Second
+	pollBackoffMax = time.Minute
+)
+
+// GetUpdates represents the payload that needs to be sent to Telegram's getUpdates method.
+type GetUpdates struct {
+	Offset         int64    `json:"offset,omitempty"`
+	Limit          int      `json:"limit,omitempty"`
+	Timeout        int      `json:"timeout,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// GetUpdates will call Telegram's getUpdates method and return the pending updates.
+func (b *Bot) GetUpdates(ctx context.Context, req *GetUpdates) ([]UpdateResponse, error) {
+	var result UpdatesResult
+	if err := b.genericPost(ctx, "getUpdates", req, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
+
+// StartPolling will receive updates with long polling instead of a webhook. Every update is
+// passed to Dispatch, just like HandleUpdate does for a webhook request. StartPolling blocks
+// until ctx is cancelled, flushes any album still being collected, and then returns nil.
+//
+// Telegram will refuse getUpdates while a webhook is registered.
+func (b *Bot) StartPolling(ctx context.Context) error {
+	defer b.FlushAlbums()
+
+	timeout := b.PollTimeout
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
+	}
+
+	req := &GetUpdates{
+		Timeout: int(timeout / time.Second),
+	}
+
+	backoff := pollBackoffMin
+	for {
+		updates, err := b.GetUpdates(ctx, req)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			log.Printf("error: could not get updates, retrying in %s: %s\n", backoff, err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+
+			if backoff *= 2; backoff > pollBackoffMax {
+				backoff = pollBackoffMax
+			}
+
+			continue
+		}
+
+		backoff = pollBackoffMin
+
+		for i := range updates {
+			ur := &updates[i]
+			req.Offset = ur.UpdateID + 1
+
+			if err := b.Dispatch(ctx, ur); err != nil {
+				log.Printf("error: could not handle update %d: %s\n", ur.UpdateID, err)
+			}
+		}
+	}
+}
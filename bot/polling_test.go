@@ -0,0 +1,114 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeServerBot(t *testing.T, h http.HandlerFunc) *Bot {
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	return New("Test_Bot", "mysecrettoken", WithBaseURL(server.URL))
+}
+
+func TestStartPolling(t *testing.T) {
+	defer func(min time.Duration) { pollBackoffMin = min }(pollBackoffMin)
+	pollBackoffMin = time.Millisecond
+
+	var mu sync.Mutex
+	var offsets []int64
+	calls := 0
+
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botmysecrettoken/getUpdates", r.URL.Path)
+
+		var req GetUpdates
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, 30, req.Timeout)
+
+		mu.Lock()
+		calls++
+		call := calls
+		offsets = append(offsets, req.Offset)
+		mu.Unlock()
+
+		switch call {
+		case 1:
+			fmt.Fprint(w, `{"ok":true,"result":[{"update_id":10,"message":{"message_id":1,"from":{"id":1,"first_name":"John"},"chat":{"id":2,"type":"private"},"text":"first"}},{"update_id":11,"message":{"message_id":2,"from":{"id":1,"first_name":"John"},"chat":{"id":2,"type":"private"},"text":"second"}}]}`)
+		case 2:
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"ok":false,"error_code":409,"description":"Conflict"}`)
+		case 3:
+			fmt.Fprint(w, `{"ok":true,"result":[{"update_id":12,"message":{"message_id":3,"from":{"id":1,"first_name":"John"},"chat":{"id":2,"type":"private"},"text":"third"}}]}`)
+		default:
+			<-r.Context().Done()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var texts []string
+	b.SetDefaultHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		texts = append(texts, ur.Message.Text)
+		if len(texts) == 3 {
+			cancel()
+		}
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- b.StartPolling(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("StartPolling did not stop after the context was cancelled")
+	}
+
+	assert.Equal(t, []string{"first", "second", "third"}, texts)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int64{0, 12, 12}, offsets[:3])
+}
+
+func TestStartPollingFlushesAlbums(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+
+		if calls++; calls == 1 {
+			fmt.Fprint(w, `{"ok":true,"result":[{"update_id":10,"message":{"message_id":1,"chat":{"id":2,"type":"private"},"media_group_id":"a1"}},{"update_id":11,"message":{"message_id":2,"chat":{"id":2,"type":"private"},"media_group_id":"a1"}}]}`)
+			return
+		}
+
+		// the album was dispatched before the next getUpdates
+		cancel()
+		<-r.Context().Done()
+	})
+	b.AlbumTimeout = time.Hour
+
+	var handled []*UpdateResponse
+	b.SetAlbumHandler(func(ctx context.Context, b *Bot, updates []*UpdateResponse) {
+		handled = updates
+	})
+
+	assert.NoError(t, b.StartPolling(ctx))
+	assert.Len(t, handled, 2, "the album was handled when polling stopped")
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// PollTally keeps the votes of non-anonymous polls in memory, built from poll_answer updates.
+// Unlike the counts in a Poll, it knows who voted for what. It is safe for concurrent use.
+//
+// Example:
+//
+//	tally := bot.NewPollTally()
+//	b.SetPollAnswerHandler(tally.HandlePollAnswer)
+type PollTally struct {
+	mu    sync.Mutex
+	polls map[string]map[int64][]int // poll ID to the options of each voter
+}
+
+// NewPollTally returns an empty PollTally.
+func NewPollTally() *PollTally {
+	return &PollTally{polls: make(map[string]map[int64][]int)}
+}
+
+// Add records a vote. A later answer of the same voter replaces the earlier one, and an answer
+// without options retracts the vote.
+func (t *PollTally) Add(a *PollAnswer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	votes := t.polls[a.PollID]
+	if votes == nil {
+		votes = make(map[int64][]int)
+		t.polls[a.PollID] = votes
+	}
+
+	if len(a.OptionIDs) == 0 {
+		delete(votes, a.VoterID())
+		return
+	}
+
+	votes[a.VoterID()] = append([]int(nil), a.OptionIDs...)
+}
+
+// HandlePollAnswer is a Handler which adds the vote of ur.PollAnswer to the tally.
+func (t *PollTally) HandlePollAnswer(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+	if ur.PollAnswer != nil {
+		t.Add(ur.PollAnswer)
+	}
+}
+
+// Votes returns the options each voter chose in a poll, keyed by VoterID.
+func (t *PollTally) Votes(pollID string) map[int64][]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	votes := make(map[int64][]int, len(t.polls[pollID]))
+	for voter, options := range t.polls[pollID] {
+		votes[voter] = append([]int(nil), options...)
+	}
+
+	return votes
+}
+
+// Counts returns how many voters chose each option of a poll, keyed by option index.
+func (t *PollTally) Counts(pollID string) map[int]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[int]int)
+	for _, options := range t.polls[pollID] {
+		for _, option := range options {
+			counts[option]++
+		}
+	}
+
+	return counts
+}
+
+// Voters returns the IDs of the voters who chose option in a poll, in ascending order.
+func (t *PollTally) Voters(pollID string, option int) []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var voters []int64
+	for voter, options := range t.polls[pollID] {
+		for _, o := range options {
+			if o == option {
+				voters = append(voters, voter)
+				break
+			}
+		}
+	}
+
+	sort.Slice(voters, func(i, j int) bool { return voters[i] < voters[j] })
+	return voters
+}
+
+// Delete forgets the votes of a poll, e.g. once it was stopped and evaluated.
+func (t *PollTally) Delete(pollID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.polls, pollID)
+}
@@ -0,0 +1,246 @@
+package bot
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Count calls every Per, with bursts of up to Count calls.
+type Limit struct {
+	Count int
+	Per   time.Duration
+}
+
+// RateLimits configures a RateLimiter. Fields left at zero use Telegram's documented limits.
+type RateLimits struct {
+	Global      Limit // across all chats, defaults to 30 per second
+	PrivateChat Limit // per private chat, defaults to 1 per second
+	GroupChat   Limit // per group or supergroup, defaults to 20 per minute
+	Channel     Limit // per channel, defaults to GroupChat
+}
+
+// Telegram's documented limits for outgoing messages.
+var (
+	DefaultGlobalLimit      = Limit{30, time.Second}
+	DefaultPrivateChatLimit = Limit{1, time.Second}
+	DefaultGroupChatLimit   = Limit{20, time.Minute}
+)
+
+// sweepThreshold is how many chat buckets are kept before idle ones are removed.
+const sweepThreshold = 1024
+
+// RateLimiter throttles outgoing messages with a global token bucket and a token bucket per chat.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	limits RateLimits
+
+	mutex    sync.Mutex
+	global   *bucket
+	chats    map[int64]*bucket
+	channels map[int64]bool
+}
+
+// NewRateLimiter returns a new RateLimiter.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	if limits.Global.Count <= 0 || limits.Global.Per <= 0 {
+		limits.Global = DefaultGlobalLimit
+	}
+
+	if limits.PrivateChat.Count <= 0 || limits.PrivateChat.Per <= 0 {
+		limits.PrivateChat = DefaultPrivateChatLimit
+	}
+
+	if limits.GroupChat.Count <= 0 || limits.GroupChat.Per <= 0 {
+		limits.GroupChat = DefaultGroupChatLimit
+	}
+
+	if limits.Channel.Count <= 0 || limits.Channel.Per <= 0 {
+		limits.Channel = limits.GroupChat
+	}
+
+	return &RateLimiter{
+		limits:   limits,
+		global:   newBucket(limits.Global, time.Now()),
+		chats:    make(map[int64]*bucket),
+		channels: make(map[int64]bool),
+	}
+}
+
+// SetChatType tells the limiter the type of a chat, so the right limit is used for it. Dispatch
+// calls it for the chat of every update when the Bot has a RateLimiter. Chats of unknown type
+// are told apart by their ID: users have positive IDs, groups and channels negative ones, and
+// the GroupChat limit is used for the latter.
+func (l *RateLimiter) SetChatType(chatID int64, chatType string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if chatType == ChatTypeChannel {
+		l.channels[chatID] = true
+	} else {
+		delete(l.channels, chatID)
+	}
+}
+
+// Wait blocks until a message may be sent to chatID or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, chatID int64) error {
+	return l.WaitN(ctx, chatID, 1)
+}
+
+// WaitN is like Wait, but for n messages sent with one call, such as the items of an album.
+func (l *RateLimiter) WaitN(ctx context.Context, chatID int64, n int) error {
+	for {
+		wait := l.reserve(chatID, n, time.Now())
+		if wait == 0 {
+			return nil
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes n tokens from both buckets once they hold at least one. Otherwise it returns how
+// long to wait before trying again. Buckets may go into debt, so n can exceed their capacity.
+func (l *RateLimiter) reserve(chatID int64, n int, now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	chat, ok := l.chats[chatID]
+	if !ok {
+		if len(l.chats) >= sweepThreshold {
+			l.sweep(now)
+		}
+
+		limit := l.limits.PrivateChat
+		switch {
+		case l.channels[chatID]:
+			limit = l.limits.Channel
+		case chatID < 0:
+			limit = l.limits.GroupChat
+		}
+
+		chat = newBucket(limit, now)
+		l.chats[chatID] = chat
+	}
+
+	wait := l.global.wait(now)
+	if w := chat.wait(now); w > wait {
+		wait = w
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	l.global.tokens -= float64(n)
+	chat.tokens -= float64(n)
+	return 0
+}
+
+// sweep removes buckets that have refilled completely, since they behave like new ones. Chat
+// types are kept.
+func (l *RateLimiter) sweep(now time.Time) {
+	for id, b := range l.chats {
+		if b.refill(now); b.tokens >= b.capacity {
+			delete(l.chats, id)
+		}
+	}
+}
+
+type bucket struct {
+	capacity float64
+	tokens   float64
+	interval time.Duration // time to earn one token
+	last     time.Time
+}
+
+func newBucket(limit Limit, now time.Time) *bucket {
+	return &bucket{
+		capacity: float64(limit.Count),
+		tokens:   float64(limit.Count),
+		interval: limit.Per / time.Duration(limit.Count),
+		last:     now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(b.interval)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+
+		b.last = now
+	}
+}
+
+// wait returns how long until the bucket holds a whole token, including any debt.
+func (b *bucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil((1 - b.tokens) * float64(b.interval)))
+}
+
+// chatTarget is implemented by payloads which send or edit a message in a chat. Calls with
+// these payloads go through the Bot's RateLimiter.
+type chatTarget interface {
+	targetChatID() int64
+}
+
+func (m *SendMessage) targetChatID() int64     { return m.ChatID }
+func (m *EditMessageText) targetChatID() int64 { return m.ChatID }
+func (m *SendDocument) targetChatID() int64    { return m.ChatID }
+func (m *SendPhoto) targetChatID() int64       { return m.ChatID }
+func (m *SendAudio) targetChatID() int64       { return m.ChatID }
+func (m *SendVideo) targetChatID() int64       { return m.ChatID }
+func (m *SendAnimation) targetChatID() int64   { return m.ChatID }
+func (m *SendVoice) targetChatID() int64       { return m.ChatID }
+func (m *SendVideoNote) targetChatID() int64   { return m.ChatID }
+func (m *SendSticker) targetChatID() int64     { return m.ChatID }
+func (m *SendMediaGroup) targetChatID() int64  { return m.ChatID }
+func (m *SendPoll) targetChatID() int64        { return m.ChatID }
+func (m *StopPoll) targetChatID() int64        { return m.ChatID }
+
+// messageCounter is implemented by chat targets which send more than one message per call.
+// Telegram counts each of them against the limits.
+type messageCounter interface {
+	messageCount() int
+}
+
+func (m *SendMediaGroup) messageCount() int { return len(m.Media) }
+
+// throttleTarget returns the chat msg is sent to, and how many messages it sends there. A zero
+// chatID means msg isn't throttled.
+func throttleTarget(msg interface{}) (chatID int64, n int) {
+	t, ok := msg.(chatTarget)
+	if !ok {
+		return 0, 0
+	}
+
+	n = 1
+	if c, ok := msg.(messageCounter); ok && c.messageCount() > 1 {
+		n = c.messageCount()
+	}
+
+	return t.targetChatID(), n
+}
+
+// throttle waits for the RateLimiter, if there is one, before n messages are sent to chatID.
+// A zero chatID is never throttled.
+func (b *Bot) throttle(ctx context.Context, chatID int64, n int) error {
+	if b.RateLimiter == nil || chatID == 0 {
+		return nil
+	}
+
+	return b.RateLimiter.WaitN(ctx, chatID, n)
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := NewRateLimiter(RateLimits{Global: Limit{3, time.Second}})
+	now := time.Now()
+
+	assert.Equal(t, time.Duration(0), l.reserve(100, 1, now))
+	assert.Equal(t, time.Second, l.reserve(100, 1, now), "1 message per second in a private chat")
+
+	assert.Equal(t, time.Duration(0), l.reserve(-200, 1, now))
+	assert.Equal(t, time.Duration(0), l.reserve(-300, 1, now))
+	assert.Equal(t, time.Second/3, l.reserve(-200, 1, now), "global limit reached")
+
+	now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), l.reserve(100, 1, now))
+	assert.Equal(t, time.Duration(0), l.reserve(-200, 1, now))
+	assert.Equal(t, time.Duration(0), l.reserve(-200, 1, now))
+	assert.Equal(t, time.Second/3, l.reserve(-300, 1, now), "global bucket is empty again")
+
+}
+
+func TestRateLimiterGroupChat(t *testing.T) {
+	l := NewRateLimiter(RateLimits{Global: Limit{1000, time.Second}})
+	now := time.Now()
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, time.Duration(0), l.reserve(-200, 1, now))
+	}
+
+	assert.Equal(t, 3*time.Second, l.reserve(-200, 1, now), "20 messages per minute in a group")
+	assert.Equal(t, time.Duration(0), l.reserve(-200, 1, now.Add(3*time.Second)))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(RateLimits{PrivateChat: Limit{1, time.Hour}})
+
+	assert.NoError(t, l.Wait(context.Background(), 100))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx, 100))
+	assert.NoError(t, l.Wait(context.Background(), 101))
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	l := NewRateLimiter(RateLimits{})
+	now := time.Now()
+
+	for i := int64(1); i <= sweepThreshold; i++ {
+		l.reserve(i, 1, now)
+	}
+	assert.Len(t, l.chats, sweepThreshold)
+
+	l.reserve(-1, 1, now.Add(time.Minute))
+	assert.Len(t, l.chats, 1)
+}
+
+func TestRateLimiterChannel(t *testing.T) {
+	l := NewRateLimiter(RateLimits{Global: Limit{1000, time.Second}, Channel: Limit{2, time.Second}})
+	now := time.Now()
+
+	// channels are told apart from groups once their type is known
+	l.SetChatType(-100, ChatTypeChannel)
+	assert.Equal(t, time.Duration(0), l.reserve(-100, 1, now))
+	assert.Equal(t, time.Duration(0), l.reserve(-100, 1, now))
+	assert.Equal(t, time.Second/2, l.reserve(-100, 1, now), "2 messages per second in the channel")
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, time.Duration(0), l.reserve(-200, 1, now))
+	}
+	assert.Equal(t, 3*time.Second, l.reserve(-200, 1, now), "20 messages per minute in a group")
+
+	// Dispatch learns the types of chats from updates
+	b := New("Test_Bot", "mysecrettoken")
+	b.SetRateLimiter(l)
+	assert.NoError(t, b.Dispatch(context.Background(), &UpdateResponse{ChannelPost: &Message{ID: 1, Chat: &Chat{ID: -300, Type: ChatTypeChannel}}}))
+	assert.True(t, l.channels[-300])
+}
+
+func TestRateLimiterAlbum(t *testing.T) {
+	l := NewRateLimiter(RateLimits{Global: Limit{1000, time.Second}})
+	now := time.Now()
+
+	// every item of an album counts, even if there are more than the bucket holds
+	assert.Equal(t, time.Duration(0), l.reserve(100, 3, now))
+	assert.Equal(t, 3*time.Second, l.reserve(100, 1, now))
+	assert.Equal(t, time.Duration(0), l.reserve(100, 1, now.Add(3*time.Second)))
+
+	album := &SendMediaGroup{ChatID: 100}
+	album.AddPhoto(FromFileID("a"), "").AddPhoto(FromFileID("b"), "").AddPhoto(FromFileID("c"), "")
+	chatID, n := throttleTarget(album)
+	assert.Equal(t, int64(100), chatID)
+	assert.Equal(t, 3, n)
+
+	chatID, n = throttleTarget(&SendMessage{ChatID: 100})
+	assert.Equal(t, int64(100), chatID)
+	assert.Equal(t, 1, n)
+}
@@ -3,9 +3,16 @@ package bot
 import "encoding/json"
 
 type GenericResult struct {
-	OK          bool   `json:"ok"`
-	ErrorCode   int    `json:"error_code,omitempty"`
-	Description string `json:"description,omitempty"`
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+// ResponseParameters contains information about why a request was unsuccessful.
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"`
 }
 
 // Result represents the result of a SendMessage result.
@@ -27,3 +34,30 @@ func (m *MessageResult) String() string {
 
 	return string(b)
 }
+
+// WebhookInfoResult represents the result of a getWebhookInfo call.
+type WebhookInfoResult struct {
+	GenericResult
+	Result *WebhookInfo `json:"result"`
+}
+
+// RawResult represents the result of any call, with the result left undecoded.
+type RawResult struct {
+	GenericResult
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// UpdatesResult represents the result of a getUpdates call.
+type UpdatesResult struct {
+	GenericResult
+	Result []UpdateResponse `json:"result"`
+}
+
+// apiResult is implemented by every result type that embeds GenericResult.
+type apiResult interface {
+	genericResult() *GenericResult
+}
+
+func (g *GenericResult) genericResult() *GenericResult {
+	return g
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Defaults used for any RetryPolicy field left at zero.
+const (
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy describes how failed API calls are repeated.
+//
+// A flood wait (429) is repeated after exactly the retry_after Telegram asks for. Server errors
+// (5xx) and network errors back off exponentially starting at BaseDelay. Only getters and a few
+// setters which can safely be repeated are retried after the request may have reached Telegram.
+// Any other method, such as sendMessage or answerCallbackQuery, is only retried when Telegram is
+// known not to have performed it: after a flood wait, or when the connection could not be
+// established.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. 0 or 1 disables
+	// retries.
+	MaxAttempts int
+
+	// BaseDelay is the first exponential backoff delay. Defaults to DefaultRetryBaseDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps every wait. A flood wait longer than MaxDelay is not retried.
+	// Defaults to DefaultRetryMaxDelay.
+	MaxDelay time.Duration
+}
+
+// retry returns how long to wait before repeating a call to method which failed with err.
+func (p *RetryPolicy) retry(attempt int, method string, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.ErrorCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0:
+			return apiErr.RetryAfter, apiErr.RetryAfter <= maxDelay
+		case apiErr.ErrorCode == http.StatusTooManyRequests:
+			return p.backoff(attempt, maxDelay), true
+		case apiErr.ErrorCode >= http.StatusInternalServerError:
+			return p.backoff(attempt, maxDelay), isIdempotent(method)
+		}
+
+		return 0, false
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		// not a network error, e.g. a response that couldn't be decoded
+		return 0, false
+	}
+
+	return p.backoff(attempt, maxDelay), isIdempotent(method) || !requestSent(err)
+}
+
+func (p *RetryPolicy) backoff(attempt int, maxDelay time.Duration) time.Duration {
+	delay := p.BaseDelay
+	if delay <= 0 {
+		delay = DefaultRetryBaseDelay
+	}
+
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// idempotentMethods are the methods besides getters which can be repeated without side effects.
+var idempotentMethods = map[string]bool{
+	"downloadFile":                    true,
+	"setMyCommands":                   true,
+	"deleteMyCommands":                true,
+	"setMyName":                       true,
+	"setMyDescription":                true,
+	"setMyShortDescription":           true,
+	"setMyDefaultAdministratorRights": true,
+	"setChatMenuButton":               true,
+}
+
+// isIdempotent returns true if repeating the method can't have any effect beyond the first call.
+// Methods that aren't known to be safe, e.g. because a repeated call sends a duplicate or fails
+// with "query is too old", return false.
+func isIdempotent(method string) bool {
+	return strings.HasPrefix(method, "get") || idempotentMethods[method]
+}
+
+// wait blocks for d, and returns false if ctx is done before.
+func (b *Bot) wait(ctx context.Context, d time.Duration) bool {
+	if b.sleep != nil {
+		return b.sleep(ctx, d)
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// requestSent returns false if err shows the request never left this host.
+func requestSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+
+	return true
+}
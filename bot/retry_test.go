@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	flood := &APIError{ErrorCode: 429, RetryAfter: 7 * time.Second}
+	wait, ok := p.retry(1, "sendMessage", flood)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	_, ok = p.retry(3, "sendMessage", flood)
+	assert.False(t, ok, "no attempts left")
+
+	_, ok = p.retry(1, "sendMessage", &APIError{ErrorCode: 429, RetryAfter: time.Minute})
+	assert.False(t, ok, "flood wait longer than MaxDelay")
+
+	serverErr := &APIError{ErrorCode: 502}
+	wait, ok = p.retry(2, "getChatMember", serverErr)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	_, ok = p.retry(1, "sendMessage", serverErr)
+	assert.False(t, ok, "sendMessage reached Telegram")
+
+	_, ok = p.retry(1, "sendMessage", &APIError{ErrorCode: 400})
+	assert.False(t, ok)
+
+	dialErr := &url.Error{Op: "Post", URL: "https://api.telegram.org", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	_, ok = p.retry(1, "sendMessage", dialErr)
+	assert.True(t, ok, "request never left the host")
+
+	readErr := &url.Error{Op: "Post", URL: "https://api.telegram.org", Err: &net.OpError{Op: "read", Err: errors.New("connection reset")}}
+	_, ok = p.retry(1, "sendMessage", readErr)
+	assert.False(t, ok)
+	_, ok = p.retry(1, "getChat", readErr)
+	assert.True(t, ok)
+	_, ok = p.retry(1, "setMyCommands", readErr)
+	assert.True(t, ok)
+
+	// methods that aren't known to be safe to repeat
+	for _, method := range []string{"editMessageText", "answerCallbackQuery", "answerInlineQuery", "createChatInviteLink", "stopPoll", "approveChatJoinRequest", "setWebhook", "banChatMember"} {
+		_, ok = p.retry(1, method, readErr)
+		assert.False(t, ok, method)
+		_, ok = p.retry(1, method, serverErr)
+		assert.False(t, ok, method)
+		_, ok = p.retry(1, method, dialErr)
+		assert.True(t, ok, method)
+	}
+
+	_, ok = (&RetryPolicy{MaxAttempts: 1}).retry(1, "getChatMember", serverErr)
+	assert.False(t, ok, "a single attempt")
+	_, ok = (&RetryPolicy{}).retry(1, "getChatMember", serverErr)
+	assert.False(t, ok, "no attempts set")
+
+	_, ok = (*RetryPolicy)(nil).retry(1, "getChatMember", serverErr)
+	assert.False(t, ok)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	assert.Equal(t, time.Second, p.backoff(1, p.MaxDelay))
+	assert.Equal(t, 2*time.Second, p.backoff(2, p.MaxDelay))
+	assert.Equal(t, 4*time.Second, p.backoff(3, p.MaxDelay))
+	assert.Equal(t, 5*time.Second, p.backoff(4, p.MaxDelay))
+	assert.Equal(t, 5*time.Second, p.backoff(9, p.MaxDelay))
+}
+
+func TestRetryFloodWait(t *testing.T) {
+	calls := 0
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":12345,"text":"test"}}`)
+	})
+	b.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2})
+
+	var waits []time.Duration
+	b.sleep = func(ctx context.Context, d time.Duration) bool {
+		waits = append(waits, d)
+		return true
+	}
+
+	result, err := b.PostSendMessage(&SendMessage{ChatID: 1000, Text: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), result.Result.ID)
+	assert.Equal(t, 0, result.ErrorCode)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []time.Duration{time.Second}, waits)
+}
@@ -1,7 +1,10 @@
 package bot
 
-// ReplyMarkup actually contains three Telegram objects in one: the ReplyKeyboardMarkup, ReplyKeyboardHide, and ForceReply objects.
+// ReplyMarkup actually contains four Telegram objects in one: the InlineKeyboardMarkup, ReplyKeyboardMarkup, ReplyKeyboardHide, and ForceReply objects.
 type ReplyMarkup struct {
+	// InlineKeyboardMarkup
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard,omitempty"`
+
 	// ReplyKeyboardMarkup
 	Keyboard        [][]string `json:"keyboard,omitempty"`
 	ResizeKeyboard  bool       `json:"resize_keyboard,omitempty"`
@@ -38,10 +41,138 @@ type EditMessageText struct {
 	ReplyMarkup           *ReplyMarkup `json:"reply_markup,omitempty"`
 }
 
+// Parse modes for the text and captions of a message.
+const (
+	ParseModeMarkdown   = "Markdown"
+	ParseModeMarkdownV2 = "MarkdownV2"
+	ParseModeHTML       = "HTML"
+)
+
 // SendDocument represents the payload that needs to be sent to Telegram's sendDocument method.
 type SendDocument struct {
-	ChatID           int64        `json:"chat_id"`
-	Document         string       `json:"-"`
-	ReplyToMessageID int64        `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup      *ReplyMarkup `json:"reply_markup"`
+	ChatID                      int64           `json:"chat_id"`
+	MessageThreadID             int64           `json:"message_thread_id,omitempty"`
+	Document                    *InputFile      `json:"document"`
+	Thumbnail                   *InputFile      `json:"thumbnail,omitempty"`
+	Caption                     string          `json:"caption,omitempty"`
+	ParseMode                   string          `json:"parse_mode,omitempty"`
+	CaptionEntities             []MessageEntity `json:"caption_entities,omitempty"`
+	DisableContentTypeDetection bool            `json:"disable_content_type_detection,omitempty"`
+	DisableNotification         bool            `json:"disable_notification,omitempty"`
+	ProtectContent              bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID            int64           `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup                 *ReplyMarkup    `json:"reply_markup"`
+}
+
+// SendPhoto represents the payload that needs to be sent to Telegram's sendPhoto method.
+type SendPhoto struct {
+	ChatID              int64           `json:"chat_id"`
+	MessageThreadID     int64           `json:"message_thread_id,omitempty"`
+	Photo               *InputFile      `json:"photo"`
+	Caption             string          `json:"caption,omitempty"`
+	ParseMode           string          `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity `json:"caption_entities,omitempty"`
+	HasSpoiler          bool            `json:"has_spoiler,omitempty"`
+	DisableNotification bool            `json:"disable_notification,omitempty"`
+	ProtectContent      bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64           `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup    `json:"reply_markup,omitempty"`
+}
+
+// SendAudio represents the payload that needs to be sent to Telegram's sendAudio method.
+type SendAudio struct {
+	ChatID              int64           `json:"chat_id"`
+	MessageThreadID     int64           `json:"message_thread_id,omitempty"`
+	Audio               *InputFile      `json:"audio"`
+	Thumbnail           *InputFile      `json:"thumbnail,omitempty"`
+	Caption             string          `json:"caption,omitempty"`
+	ParseMode           string          `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity `json:"caption_entities,omitempty"`
+	Duration            int             `json:"duration,omitempty"`
+	Performer           string          `json:"performer,omitempty"`
+	Title               string          `json:"title,omitempty"`
+	DisableNotification bool            `json:"disable_notification,omitempty"`
+	ProtectContent      bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64           `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup    `json:"reply_markup,omitempty"`
+}
+
+// SendVideo represents the payload that needs to be sent to Telegram's sendVideo method.
+type SendVideo struct {
+	ChatID              int64           `json:"chat_id"`
+	MessageThreadID     int64           `json:"message_thread_id,omitempty"`
+	Video               *InputFile      `json:"video"`
+	Thumbnail           *InputFile      `json:"thumbnail,omitempty"`
+	Caption             string          `json:"caption,omitempty"`
+	ParseMode           string          `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity `json:"caption_entities,omitempty"`
+	Duration            int             `json:"duration,omitempty"`
+	Width               int             `json:"width,omitempty"`
+	Height              int             `json:"height,omitempty"`
+	HasSpoiler          bool            `json:"has_spoiler,omitempty"`
+	SupportsStreaming   bool            `json:"supports_streaming,omitempty"`
+	DisableNotification bool            `json:"disable_notification,omitempty"`
+	ProtectContent      bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64           `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup    `json:"reply_markup,omitempty"`
+}
+
+// SendAnimation represents the payload that needs to be sent to Telegram's sendAnimation method.
+type SendAnimation struct {
+	ChatID              int64           `json:"chat_id"`
+	MessageThreadID     int64           `json:"message_thread_id,omitempty"`
+	Animation           *InputFile      `json:"animation"`
+	Thumbnail           *InputFile      `json:"thumbnail,omitempty"`
+	Caption             string          `json:"caption,omitempty"`
+	ParseMode           string          `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity `json:"caption_entities,omitempty"`
+	Duration            int             `json:"duration,omitempty"`
+	Width               int             `json:"width,omitempty"`
+	Height              int             `json:"height,omitempty"`
+	HasSpoiler          bool            `json:"has_spoiler,omitempty"`
+	DisableNotification bool            `json:"disable_notification,omitempty"`
+	ProtectContent      bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64           `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup    `json:"reply_markup,omitempty"`
+}
+
+// SendVoice represents the payload that needs to be sent to Telegram's sendVoice method.
+type SendVoice struct {
+	ChatID              int64           `json:"chat_id"`
+	MessageThreadID     int64           `json:"message_thread_id,omitempty"`
+	Voice               *InputFile      `json:"voice"`
+	Caption             string          `json:"caption,omitempty"`
+	ParseMode           string          `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity `json:"caption_entities,omitempty"`
+	Duration            int             `json:"duration,omitempty"`
+	DisableNotification bool            `json:"disable_notification,omitempty"`
+	ProtectContent      bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64           `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup    `json:"reply_markup,omitempty"`
+}
+
+// SendVideoNote represents the payload that needs to be sent to Telegram's sendVideoNote method.
+type SendVideoNote struct {
+	ChatID              int64        `json:"chat_id"`
+	MessageThreadID     int64        `json:"message_thread_id,omitempty"`
+	VideoNote           *InputFile   `json:"video_note"`
+	Thumbnail           *InputFile   `json:"thumbnail,omitempty"`
+	Duration            int          `json:"duration,omitempty"`
+	Length              int          `json:"length,omitempty"` // diameter of the video
+	DisableNotification bool         `json:"disable_notification,omitempty"`
+	ProtectContent      bool         `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64        `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup `json:"reply_markup,omitempty"`
+}
+
+// SendSticker represents the payload that needs to be sent to Telegram's sendSticker method.
+type SendSticker struct {
+	ChatID              int64        `json:"chat_id"`
+	MessageThreadID     int64        `json:"message_thread_id,omitempty"`
+	Sticker             *InputFile   `json:"sticker"`
+	Emoji               string       `json:"emoji,omitempty"`
+	DisableNotification bool         `json:"disable_notification,omitempty"`
+	ProtectContent      bool         `json:"protect_content,omitempty"`
+	ReplyToMessageID    int64        `json:"reply_to_message_id,omitempty"`
+	ReplyMarkup         *ReplyMarkup `json:"reply_markup,omitempty"`
 }
@@ -15,11 +15,19 @@ const (
 
 // UpdateResponse represents a response from a Telegram getUpdates method call.
 type UpdateResponse struct {
-	UpdateID          int64    `json:"update_id"`
-	Message           *Message `json:"message"`
-	EditedMessage     *Message `json:"edited_message"`
-	ChannelPost       *Message `json:"channel_post"`
-	EditedChannelPost *Message `json:"edited_channel_post"`
+	UpdateID           int64               `json:"update_id"`
+	Message            *Message            `json:"message"`
+	EditedMessage      *Message            `json:"edited_message"`
+	ChannelPost        *Message            `json:"channel_post"`
+	EditedChannelPost  *Message            `json:"edited_channel_post"`
+	CallbackQuery      *CallbackQuery      `json:"callback_query"`
+	InlineQuery        *InlineQuery        `json:"inline_query"`
+	ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result"`
+	MyChatMember       *ChatMemberUpdated  `json:"my_chat_member"`
+	ChatMember         *ChatMemberUpdated  `json:"chat_member"`
+	ChatJoinRequest    *ChatJoinRequest    `json:"chat_join_request"`
+	Poll               *Poll               `json:"poll"`
+	PollAnswer         *PollAnswer         `json:"poll_answer"`
 }
 
 // Chat represents a Telegram chat.
@@ -34,58 +42,171 @@ type Chat struct {
 
 // Message represents a Telegram message.
 type Message struct {
-	ID             int64    `json:"message_id"`
-	From           *User    `json:"from,omitempty"`
-	Date           int      `json:"date"`
-	Chat           *Chat    `json:"chat"`
-	ForwardFrom    *User    `json:"forward_from,omitempty"`
-	ForwardDate    int      `json:"forward_date,omitempty"`
-	ReplyToMessage *Message `json:"reply_to_message,omitempty"`
-	Text           string   `json:"text,omitempty"`
-	// Audio
-	// Document
-	// Photo
-	// Sticker
-	// Video
-	// Voice
-	// Caption
-	// Contact
-	// Location
-	NewChatParticipant  *User  `json:"new_chat_participant,omitempty"`
-	LeftChatParticipant *User  `json:"left_chat_participant,omitempty"`
-	NewChatTitle        string `json:"new_chat_title,omitempty"`
-	// NewChatPhoto
-	DeleteChatPhoto       bool `json:"delete_chat_photo,omitempty"`
-	GroupChatCreated      bool `json:"group_chat_created,omitempty"`
-	SupergroupChatCreated bool `json:"supergroup_chat_created,omitempty"`
-	// ChannelChatCreated
-	MigrateToChatID   int64 `json:"migrate_to_chat_id,omitempty"`
-	MigrateFromChatID int64 `json:"migrate_from_chat_id,omitempty"`
+	ID                  int64           `json:"message_id"`
+	MessageThreadID     int64           `json:"message_thread_id,omitempty"`
+	From                *User           `json:"from,omitempty"`
+	SenderChat          *Chat           `json:"sender_chat,omitempty"`
+	Date                int             `json:"date"`
+	Chat                *Chat           `json:"chat"`
+	ForwardOrigin       *MessageOrigin  `json:"forward_origin,omitempty"`
+	ForwardFrom         *User           `json:"forward_from,omitempty"`
+	ForwardDate         int             `json:"forward_date,omitempty"`
+	IsTopicMessage      bool            `json:"is_topic_message,omitempty"`
+	IsAutomaticForward  bool            `json:"is_automatic_forward,omitempty"`
+	ReplyToMessage      *Message        `json:"reply_to_message,omitempty"`
+	ViaBot              *User           `json:"via_bot,omitempty"`
+	EditDate            int             `json:"edit_date,omitempty"`
+	HasProtectedContent bool            `json:"has_protected_content,omitempty"`
+	AuthorSignature     string          `json:"author_signature,omitempty"`
+	Text                string          `json:"text,omitempty"`
+	Entities            []MessageEntity `json:"entities,omitempty"`
+	MediaGroupID        string          `json:"media_group_id,omitempty"`
+
+	// Media
+	Animation       *Animation      `json:"animation,omitempty"`
+	Audio           *Audio          `json:"audio,omitempty"`
+	Document        *Document       `json:"document,omitempty"`
+	Photo           []PhotoSize     `json:"photo,omitempty"`
+	Sticker         *Sticker        `json:"sticker,omitempty"`
+	Video           *Video          `json:"video,omitempty"`
+	VideoNote       *VideoNote      `json:"video_note,omitempty"`
+	Voice           *Voice          `json:"voice,omitempty"`
+	Caption         string          `json:"caption,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	HasMediaSpoiler bool            `json:"has_media_spoiler,omitempty"`
+	Contact         *Contact        `json:"contact,omitempty"`
+	Dice            *Dice           `json:"dice,omitempty"`
+	Poll            *Poll           `json:"poll,omitempty"`
+	Venue           *Venue          `json:"venue,omitempty"`
+	Location        *Location       `json:"location,omitempty"`
+
+	// Service messages
+	NewChatMembers                []User                         `json:"new_chat_members,omitempty"`
+	LeftChatMember                *User                          `json:"left_chat_member,omitempty"`
+	NewChatParticipant            *User                          `json:"new_chat_participant,omitempty"`  // deprecated, use NewChatMembers
+	LeftChatParticipant           *User                          `json:"left_chat_participant,omitempty"` // deprecated, use LeftChatMember
+	NewChatTitle                  string                         `json:"new_chat_title,omitempty"`
+	NewChatPhoto                  []PhotoSize                    `json:"new_chat_photo,omitempty"`
+	DeleteChatPhoto               bool                           `json:"delete_chat_photo,omitempty"`
+	GroupChatCreated              bool                           `json:"group_chat_created,omitempty"`
+	SupergroupChatCreated         bool                           `json:"supergroup_chat_created,omitempty"`
+	ChannelChatCreated            bool                           `json:"channel_chat_created,omitempty"`
+	MessageAutoDeleteTimerChanged *MessageAutoDeleteTimerChanged `json:"message_auto_delete_timer_changed,omitempty"`
+	MigrateToChatID               int64                          `json:"migrate_to_chat_id,omitempty"`
+	MigrateFromChatID             int64                          `json:"migrate_from_chat_id,omitempty"`
+	PinnedMessage                 *Message                       `json:"pinned_message,omitempty"`
+	ForumTopicCreated             *ForumTopicCreated             `json:"forum_topic_created,omitempty"`
+	ForumTopicEdited              *ForumTopicEdited              `json:"forum_topic_edited,omitempty"`
+	ForumTopicClosed              *ForumTopicClosed              `json:"forum_topic_closed,omitempty"`
+	ForumTopicReopened            *ForumTopicReopened            `json:"forum_topic_reopened,omitempty"`
+	VideoChatScheduled            *VideoChatScheduled            `json:"video_chat_scheduled,omitempty"`
+	VideoChatStarted              *VideoChatStarted              `json:"video_chat_started,omitempty"`
+	VideoChatEnded                *VideoChatEnded                `json:"video_chat_ended,omitempty"`
+	VideoChatParticipantsInvited  *VideoChatParticipantsInvited  `json:"video_chat_participants_invited,omitempty"`
+
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// EffectiveMessage returns the message the update is about: a new or edited message, a new or
+// edited channel post, or the message with the button of a callback query. It returns nil if
+// there is none.
+func (ur *UpdateResponse) EffectiveMessage() *Message {
+	switch {
+	case ur.Message != nil:
+		return ur.Message
+	case ur.EditedMessage != nil:
+		return ur.EditedMessage
+	case ur.ChannelPost != nil:
+		return ur.ChannelPost
+	case ur.EditedChannelPost != nil:
+		return ur.EditedChannelPost
+	case ur.CallbackQuery != nil:
+		return ur.CallbackQuery.Message
+	}
+
+	return nil
+}
+
+// EffectiveChat returns the chat the update is about: the chat of a chat member update or join
+// request, or else the chat of EffectiveMessage. It returns nil if there is none, e.g. for inline
+// queries, polls, or callback queries from messages sent in inline mode.
+func (ur *UpdateResponse) EffectiveChat() *Chat {
+	switch {
+	case ur.MyChatMember != nil:
+		return ur.MyChatMember.Chat
+	case ur.ChatMember != nil:
+		return ur.ChatMember.Chat
+	case ur.ChatJoinRequest != nil:
+		return ur.ChatJoinRequest.Chat
+	}
+
+	if msg := ur.EffectiveMessage(); msg != nil {
+		return msg.Chat
+	}
+
+	return nil
 }
 
 // IsGroup returns true if the chat type is "group"
 func (ur *UpdateResponse) IsGroup() bool {
-	return ur.Message.Chat.Type == ChatTypeGroup || ur.Message.Chat.Type == ChatTypeSupergroup
+	chat := ur.EffectiveChat()
+	return chat != nil && (chat.Type == ChatTypeGroup || chat.Type == ChatTypeSupergroup)
 }
 
 // IsPrivate returns true if the chat type is "private"
 func (ur *UpdateResponse) IsPrivate() bool {
-	return ur.Message.Chat.Type == ChatTypePrivate
+	chat := ur.EffectiveChat()
+	return chat != nil && chat.Type == ChatTypePrivate
 }
 
-// ChatID is an accessor to p.Message.Chat.ID
+// ChatID is an accessor to the ID of EffectiveChat. For a callback query, it's the chat of the
+// message with the button. If the update has no chat, 0 is returned.
 func (ur *UpdateResponse) ChatID() int64 {
-	return ur.Message.Chat.ID
+	if chat := ur.EffectiveChat(); chat != nil {
+		return chat.ID
+	}
+
+	return 0
 }
 
-// FromID is an accessor to p.Message.From.ID
+// FromID is an accessor to the sender's ID of EffectiveMessage. For a callback query, it's the
+// user who pressed the button, for inline mode the user who sent the query, and for chat member
+// updates the user who made the change. Channel posts have no sender, so 0 is returned. For a poll
+// answer, it's the PollAnswer's VoterID.
 func (ur *UpdateResponse) FromID() int64 {
-	return ur.Message.From.ID
+	switch {
+	case ur.CallbackQuery != nil:
+		return ur.CallbackQuery.From.ID
+	case ur.InlineQuery != nil:
+		return ur.InlineQuery.From.ID
+	case ur.ChosenInlineResult != nil:
+		return ur.ChosenInlineResult.From.ID
+	case ur.MyChatMember != nil:
+		return ur.MyChatMember.From.ID
+	case ur.ChatMember != nil:
+		return ur.ChatMember.From.ID
+	case ur.ChatJoinRequest != nil:
+		return ur.ChatJoinRequest.From.ID
+	case ur.PollAnswer != nil:
+		return ur.PollAnswer.VoterID()
+	}
+
+	if msg := ur.EffectiveMessage(); msg != nil && msg.From != nil {
+		return msg.From.ID
+	}
+
+	return 0
 }
 
 // IsBotReply will return true if the message received is a reply to a message from the bot.
+// Replies to channel posts and other messages without a sender return false.
 func (ur *UpdateResponse) IsBotReply(b *Bot) bool {
-	return ur.Message != nil && ur.Message.ReplyToMessage != nil && ur.Message.ReplyToMessage.From.Username == b.BotName
+	msg := ur.EffectiveMessage()
+	if msg == nil || msg.ReplyToMessage == nil || msg.ReplyToMessage.From == nil {
+		return false
+	}
+
+	return msg.ReplyToMessage.From.Username == b.BotName
 }
 
 // String will return a string representation
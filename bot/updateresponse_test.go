@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"testing"
@@ -79,6 +80,25 @@ func TestIsBotReply(t *testing.T) {
 	b.BotName = "Test2_Bot"
 
 	assert.False(t, ur.IsBotReply(b))
+
+	// a channel post replying to another post, which has no sender
+	ur = &UpdateResponse{
+		ChannelPost: &Message{
+			Chat:           &Chat{ID: -100, Type: ChatTypeChannel},
+			ReplyToMessage: &Message{Chat: &Chat{ID: -100, Type: ChatTypeChannel}},
+		},
+	}
+
+	assert.False(t, ur.IsBotReply(b))
+
+	b = New("Test_Bot", "mysecrettoken")
+	var reply bool
+	b.SetChannelPostHandler(func(ctx context.Context, b *Bot, ur *UpdateResponse, args string) {
+		reply = ur.IsBotReply(b)
+	})
+
+	assert.NoError(t, b.Dispatch(context.Background(), ur))
+	assert.False(t, reply)
 }
 
 func TestDecode(t *testing.T) {
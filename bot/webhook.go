@@ -0,0 +1,303 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// DefaultMaxBodySize is the largest webhook request body ServeHTTP will accept when
+// Bot.MaxBodySize is zero.
+const DefaultMaxBodySize = 1 << 20
+
+// SecretTokenHeader is the header Telegram uses to send the webhook secret token.
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// ErrInvalidSecretToken is returned when a webhook request doesn't carry the expected secret token.
+var ErrInvalidSecretToken = errors.New("bot: invalid webhook secret token")
+
+// SetWebhook represents the payload that needs to be sent to Telegram's setWebhook method.
+type SetWebhook struct {
+	URL                string
+	Certificate        string // path to a public key certificate to upload
+	IPAddress          string
+	MaxConnections     int
+	AllowedUpdates     []string
+	DropPendingUpdates bool
+	SecretToken        string
+}
+
+// WebhookInfo represents the current status of a webhook as returned by getWebhookInfo.
+type WebhookInfo struct {
+	URL                          string   `json:"url"`
+	HasCustomCertificate         bool     `json:"has_custom_certificate"`
+	PendingUpdateCount           int      `json:"pending_update_count"`
+	IPAddress                    string   `json:"ip_address,omitempty"`
+	LastErrorDate                int      `json:"last_error_date,omitempty"`
+	LastErrorMessage             string   `json:"last_error_message,omitempty"`
+	LastSynchronizationErrorDate int      `json:"last_synchronization_error_date,omitempty"`
+	MaxConnections               int      `json:"max_connections,omitempty"`
+	AllowedUpdates               []string `json:"allowed_updates,omitempty"`
+}
+
+// DeleteWebhook represents the payload that needs to be sent to Telegram's deleteWebhook method.
+type DeleteWebhook struct {
+	DropPendingUpdates bool `json:"drop_pending_updates,omitempty"`
+}
+
+// ErrorHandler represents a function that reports an error returned by Dispatch while serving
+// a webhook request.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// SetErrorHandler will set the function ServeHTTP uses to report handler failures. By default
+// the error is logged and Telegram receives a 200, since an update that got an error status is
+// redelivered until it succeeds, which would block every later update. An ErrorHandler may
+// still respond with a 5xx to have an update redelivered.
+func (b *Bot) SetErrorHandler(eh ErrorHandler) {
+	b.ErrorHandler = eh
+}
+
+// SetSecretToken sets the secret token every incoming webhook request must carry in the
+// X-Telegram-Bot-Api-Secret-Token header. It should match SetWebhook.SecretToken.
+func (b *Bot) SetSecretToken(token string) {
+	b.SecretToken = token
+}
+
+// verifySecretToken checks the secret token header of a webhook request.
+func (b *Bot) verifySecretToken(r *http.Request) error {
+	if b.SecretToken == "" {
+		return nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretTokenHeader)), []byte(b.SecretToken)) != 1 {
+		return ErrInvalidSecretToken
+	}
+
+	return nil
+}
+
+// ServeHTTP implements http.Handler so the bot can be mounted directly as a webhook.
+//
+// Example:
+//
+//	http.Handle("/secretpath", b)
+//
+// Requests that aren't a POST are rejected with a 405, and bodies that can't be decoded with a
+// 400. Bodies larger than MaxBodySize are rejected with a 413, and requests without the
+// configured secret token with a 403. Errors returned by Dispatch are passed to the ErrorHandler,
+// or else logged.
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := b.verifySecretToken(r); err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	maxBodySize := b.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxBodySize)
+	defer body.Close()
+
+	var ur UpdateResponse
+	if err := json.NewDecoder(body).Decode(&ur); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := b.Dispatch(r.Context(), &ur); err != nil {
+		if b.ErrorHandler != nil {
+			b.ErrorHandler(w, r, err)
+			return
+		}
+
+		log.Printf("error: could not handle update %d: %s\n", ur.UpdateID, err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetWebhook will post a message to Telegram's setWebhook method. This will allow the bot
+// to register with Telegram for notifications.
+func (b *Bot) SetWebhook(uri string, certFile string) error {
+	return b.SetWebhookContext(context.Background(), uri, certFile)
+}
+
+// SetWebhookContext is like SetWebhook, but with a context.
+func (b *Bot) SetWebhookContext(ctx context.Context, uri string, certFile string) error {
+	return b.PostSetWebhookContext(ctx, &SetWebhook{
+		URL:         uri,
+		Certificate: certFile,
+	})
+}
+
+// PostSetWebhook will post the webhook configuration to Telegram's setWebhook method.
+func (b *Bot) PostSetWebhook(webhook *SetWebhook) error {
+	return b.PostSetWebhookContext(context.Background(), webhook)
+}
+
+// PostSetWebhookContext is like PostSetWebhook, but with a context.
+func (b *Bot) PostSetWebhookContext(ctx context.Context, webhook *SetWebhook) error {
+	var result GenericResult
+	return b.genericPost(ctx, "setWebhook", webhook, &result)
+}
+
+// EncodeMultipart implements MultipartEncoder.
+func (webhook *SetWebhook) EncodeMultipart(w *multipart.Writer) error {
+	if webhook.Certificate != "" {
+		if err := writeFile(w, "certificate", webhook.Certificate); err != nil {
+			return err
+		}
+	}
+
+	w.WriteField("url", webhook.URL)
+
+	if webhook.IPAddress != "" {
+		w.WriteField("ip_address", webhook.IPAddress)
+	}
+
+	if webhook.MaxConnections > 0 {
+		w.WriteField("max_connections", strconv.Itoa(webhook.MaxConnections))
+	}
+
+	if webhook.AllowedUpdates != nil {
+		if err := writeJSONField(w, "allowed_updates", webhook.AllowedUpdates); err != nil {
+			return err
+		}
+	}
+
+	if webhook.DropPendingUpdates {
+		w.WriteField("drop_pending_updates", "true")
+	}
+
+	if webhook.SecretToken != "" {
+		w.WriteField("secret_token", webhook.SecretToken)
+	}
+
+	return nil
+}
+
+// GetWebhookInfo will return the current webhook status from Telegram's getWebhookInfo method.
+func (b *Bot) GetWebhookInfo() (*WebhookInfo, error) {
+	return b.GetWebhookInfoContext(context.Background())
+}
+
+// GetWebhookInfoContext is like GetWebhookInfo, but with a context.
+func (b *Bot) GetWebhookInfoContext(ctx context.Context) (*WebhookInfo, error) {
+	var result WebhookInfoResult
+	if err := b.genericPost(ctx, "getWebhookInfo", struct{}{}, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
+
+// DeleteWebhook will remove the webhook integration so the bot can switch to getUpdates.
+func (b *Bot) DeleteWebhook(dropPendingUpdates bool) error {
+	return b.DeleteWebhookContext(context.Background(), dropPendingUpdates)
+}
+
+// DeleteWebhookContext is like DeleteWebhook, but with a context.
+func (b *Bot) DeleteWebhookContext(ctx context.Context, dropPendingUpdates bool) error {
+	var result GenericResult
+	return b.genericPost(ctx, "deleteWebhook", &DeleteWebhook{dropPendingUpdates}, &result)
+}
+
+// EnsureWebhook will register the webhook only if Telegram's current configuration differs from
+// the desired one, so restarts don't reset pending updates. Returns true if the webhook was set.
+//
+// Telegram never reports the secret token, so a webhook with a SecretToken is always set again,
+// in case the token was rotated. If nothing else changed, pending updates are kept even if
+// DropPendingUpdates is set.
+func (b *Bot) EnsureWebhook(webhook *SetWebhook) (bool, error) {
+	return b.EnsureWebhookContext(context.Background(), webhook)
+}
+
+// EnsureWebhookContext is like EnsureWebhook, but with a context.
+func (b *Bot) EnsureWebhookContext(ctx context.Context, webhook *SetWebhook) (bool, error) {
+	info, err := b.GetWebhookInfoContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if info != nil && webhookMatches(info, webhook) {
+		if webhook.SecretToken == "" {
+			return false, nil
+		}
+
+		// only the secret token may differ, which is no reason to drop updates
+		w := *webhook
+		w.DropPendingUpdates = false
+		webhook = &w
+	}
+
+	if err := b.PostSetWebhookContext(ctx, webhook); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// webhookMatches reports whether info already reflects the desired webhook. Options that are
+// left unset in webhook are assumed to match.
+func webhookMatches(info *WebhookInfo, webhook *SetWebhook) bool {
+	if info.URL != webhook.URL {
+		return false
+	}
+
+	if info.HasCustomCertificate != (webhook.Certificate != "") {
+		return false
+	}
+
+	if webhook.IPAddress != "" && info.IPAddress != webhook.IPAddress {
+		return false
+	}
+
+	if webhook.MaxConnections > 0 && info.MaxConnections != webhook.MaxConnections {
+		return false
+	}
+
+	if webhook.AllowedUpdates != nil && !sameUpdateTypes(info.AllowedUpdates, webhook.AllowedUpdates) {
+		return false
+	}
+
+	return true
+}
+
+func sameUpdateTypes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
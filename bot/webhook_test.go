@@ -0,0 +1,231 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeHTTP(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+
+	called := false
+	b.SetDefaultHandler(func(ctx context.Context, b *Bot, u *UpdateResponse, args string) {
+		called = true
+	})
+
+	body := `{"update_id":797498290,"message":{"message_id":5265,"from":{"id":154355043,"first_name":"Tom"},"date":1453565516,"chat":{"id":145351029,"type":"private"},"text":"hello"}}`
+
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, called)
+
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("GET", "/secretpath", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Allow"))
+
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader("{not json")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	b.MaxBodySize = 10
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader(body)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestServeHTTPHandlerError(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+
+	body := `{"update_id":797498290}`
+
+	// without an ErrorHandler the error is only logged, so Telegram doesn't redeliver the update
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// update kinds the bot doesn't know are acknowledged as well, and logged only once
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader(`{"update_id":797498291,"message_reaction":{"chat":{"id":1,"type":"private"},"message_id":5,"date":1,"old_reaction":[],"new_reaction":[{"type":"emoji","emoji":"👍"}]}}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, strings.Count(logs.String(), "\n"), logs.String())
+
+	var handled error
+	b.SetErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader(body)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, errors.New("null message found"), handled)
+}
+
+func TestServeHTTPSecretToken(t *testing.T) {
+	b := New("Test_Bot", "mysecrettoken")
+	b.SetSecretToken("s3cr3t")
+
+	body := `{"update_id":797498290,"message":{"message_id":5265,"from":{"id":154355043,"first_name":"Tom"},"date":1453565516,"chat":{"id":145351029,"type":"private"},"text":"hello"}}`
+
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("POST", "/secretpath", strings.NewReader(body)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	r := httptest.NewRequest("POST", "/secretpath", strings.NewReader(body))
+	r.Header.Set(SecretTokenHeader, "wrong")
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	r = httptest.NewRequest("POST", "/secretpath", strings.NewReader(body))
+	r.Header.Set(SecretTokenHeader, "s3cr3t")
+	w = httptest.NewRecorder()
+	b.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	r = httptest.NewRequest("POST", "/secretpath", strings.NewReader(body))
+	assert.Equal(t, ErrInvalidSecretToken, b.HandleUpdate(r))
+}
+
+func TestPostSetWebhook(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true}`)
+
+	b := New("Test_Bot", "mysecrettoken")
+	b.client = &http.Client{Transport: transport}
+
+	err := b.PostSetWebhook(&SetWebhook{
+		URL:                "https://mysite",
+		IPAddress:          "1.2.3.4",
+		MaxConnections:     10,
+		AllowedUpdates:     []string{"message", "callback_query"},
+		DropPendingUpdates: true,
+		SecretToken:        "s3cr3t",
+	})
+	assert.NoError(t, err)
+
+	req := transport.request
+	assert.Equal(t, "https://mysite", req.FormValue("url"))
+	assert.Equal(t, "1.2.3.4", req.FormValue("ip_address"))
+	assert.Equal(t, "10", req.FormValue("max_connections"))
+	assert.Equal(t, `["message","callback_query"]`, req.FormValue("allowed_updates"))
+	assert.Equal(t, "true", req.FormValue("drop_pending_updates"))
+	assert.Equal(t, "s3cr3t", req.FormValue("secret_token"))
+}
+
+func TestPostSetWebhookFailure(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":false,"error_code":400,"description":"Bad Request: bad webhook"}`)
+
+	b := New("Test_Bot", "mysecrettoken")
+	b.client = &http.Client{Transport: transport}
+
+	assert.Error(t, b.SetWebhook("http://mysite", ""))
+}
+
+func TestGetWebhookInfo(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":{"url":"https://mysite","has_custom_certificate":false,"pending_update_count":3,"last_error_date":1700000000,"last_error_message":"Connection refused","max_connections":40}}`)
+
+	b := New("Test_Bot", "mysecrettoken")
+	b.client = &http.Client{Transport: transport}
+
+	info, err := b.GetWebhookInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/getWebhookInfo", transport.request.URL.String())
+	assert.Equal(t, "https://mysite", info.URL)
+	assert.Equal(t, 3, info.PendingUpdateCount)
+	assert.Equal(t, "Connection refused", info.LastErrorMessage)
+	assert.Equal(t, 40, info.MaxConnections)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	transport := newTestRoundTripper(`{"ok":true,"result":true}`)
+
+	b := New("Test_Bot", "mysecrettoken")
+	b.client = &http.Client{Transport: transport}
+
+	assert.NoError(t, b.DeleteWebhook(true))
+	assert.Equal(t, "https://api.telegram.org/botmysecrettoken/deleteWebhook", transport.request.URL.String())
+
+	body, _ := ioutil.ReadAll(transport.request.Body)
+	assert.Equal(t, `{"drop_pending_updates":true}`, strings.TrimSpace(string(body)))
+}
+
+func TestEnsureWebhook(t *testing.T) {
+	current := `{"url":"https://mysite","has_custom_certificate":false,"pending_update_count":3,"max_connections":40,"allowed_updates":["message","callback_query"]}`
+	setCalls := 0
+
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botmysecrettoken/getWebhookInfo":
+			fmt.Fprintf(w, `{"ok":true,"result":%s}`, current)
+		case "/botmysecrettoken/setWebhook":
+			setCalls++
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	changed, err := b.EnsureWebhook(&SetWebhook{
+		URL:            "https://mysite",
+		AllowedUpdates: []string{"callback_query", "message"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, 0, setCalls)
+
+	changed, err = b.EnsureWebhook(&SetWebhook{URL: "https://mysite", MaxConnections: 100})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, setCalls)
+
+	changed, err = b.EnsureWebhook(&SetWebhook{URL: "https://othersite"})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, setCalls)
+}
+
+func TestEnsureWebhookSecretToken(t *testing.T) {
+	var sets []string
+	b := newFakeServerBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botmysecrettoken/getWebhookInfo":
+			fmt.Fprint(w, `{"ok":true,"result":{"url":"https://mysite","has_custom_certificate":false,"pending_update_count":3}}`)
+		case "/botmysecrettoken/setWebhook":
+			sets = append(sets, r.FormValue("url")+" "+r.FormValue("secret_token")+" "+r.FormValue("drop_pending_updates"))
+
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	// the registered secret is unknown, so a rotated one is always set, without dropping updates
+	webhook := &SetWebhook{URL: "https://mysite", SecretToken: "rotated", DropPendingUpdates: true}
+	changed, err := b.EnsureWebhook(webhook)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, webhook.DropPendingUpdates)
+
+	changed, err = b.EnsureWebhook(&SetWebhook{URL: "https://othersite", SecretToken: "rotated", DropPendingUpdates: true})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	assert.Equal(t, []string{"https://mysite rotated ", "https://othersite rotated true"}, sets)
+}
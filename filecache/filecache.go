@@ -0,0 +1,134 @@
+// Package filecache provides thread-safe file_id caches for github.com/weters/telegram/bot.
+package filecache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/weters/telegram/bot"
+)
+
+var (
+	_ bot.FileCache = (*MemoryCache)(nil)
+	_ bot.FileCache = (*DiskCache)(nil)
+)
+
+// MemoryCache stores file_ids in-memory.
+type MemoryCache struct {
+	fileIDs map[string]string
+	mutex   sync.RWMutex
+}
+
+// NewMemoryCache returns a new MemoryCache object.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		fileIDs: make(map[string]string),
+	}
+}
+
+// FileID returns the file_id stored for key, or an empty string if there is none.
+func (m *MemoryCache) FileID(key string) (string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.fileIDs[key], nil
+}
+
+// SetFileID stores fileID for key. An empty fileID removes key.
+func (m *MemoryCache) SetFileID(key, fileID string) error {
+	m.mutex.Lock()
+	if fileID == "" {
+		delete(m.fileIDs, key)
+	} else {
+		m.fileIDs[key] = fileID
+	}
+	m.mutex.Unlock()
+
+	return nil
+}
+
+// DiskCache stores file_ids in a JSON file, so they survive restarts. The whole file is read
+// when the cache is created and rewritten whenever a file_id is added.
+type DiskCache struct {
+	path    string
+	fileIDs map[string]string
+	mutex   sync.RWMutex
+}
+
+// NewDiskCache returns a new DiskCache object which keeps its file_ids at path. The file is
+// created when the first file_id is stored.
+func NewDiskCache(path string) (*DiskCache, error) {
+	d := &DiskCache{
+		path:    path,
+		fileIDs: make(map[string]string),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &d.fileIDs); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// FileID returns the file_id stored for key, or an empty string if there is none.
+func (d *DiskCache) FileID(key string) (string, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.fileIDs[key], nil
+}
+
+// SetFileID stores fileID for key and writes the cache to disk. An empty fileID removes key.
+func (d *DiskCache) SetFileID(key, fileID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.fileIDs[key] == fileID {
+		return nil
+	}
+
+	if fileID == "" {
+		delete(d.fileIDs, key)
+	} else {
+		d.fileIDs[key] = fileID
+	}
+
+	return d.save()
+}
+
+// save replaces the file on disk, so a crash while writing can't leave it half written.
+func (d *DiskCache) save() error {
+	data, err := json.Marshal(d.fileIDs)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(d.path), filepath.Base(d.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), d.path)
+}
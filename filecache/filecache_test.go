@@ -0,0 +1,78 @@
+package filecache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache()
+
+	fileID, err := c.FileID("document:abc")
+	assert.NoError(t, err)
+	assert.Empty(t, fileID)
+
+	assert.NoError(t, c.SetFileID("document:abc", "BQACAgIAAx"))
+
+	fileID, err = c.FileID("document:abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "BQACAgIAAx", fileID)
+
+	// an empty file_id removes the entry
+	assert.NoError(t, c.SetFileID("document:abc", ""))
+	assert.Empty(t, c.fileIDs)
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filecache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file_ids.json")
+
+	c, err := NewDiskCache(path)
+	assert.NoError(t, err)
+
+	fileID, err := c.FileID("document:abc")
+	assert.NoError(t, err)
+	assert.Empty(t, fileID)
+
+	assert.NoError(t, c.SetFileID("document:abc", "BQACAgIAAx"))
+	assert.NoError(t, c.SetFileID("photo:def", "AgACAgIAAx"))
+
+	// a new cache reads what the first one wrote
+	c, err = NewDiskCache(path)
+	assert.NoError(t, err)
+
+	fileID, err = c.FileID("document:abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "BQACAgIAAx", fileID)
+
+	fileID, err = c.FileID("photo:def")
+	assert.NoError(t, err)
+	assert.Equal(t, "AgACAgIAAx", fileID)
+
+	files, _ := ioutil.ReadDir(dir)
+	assert.Len(t, files, 1, "temporary files are removed")
+
+	assert.NoError(t, c.SetFileID("photo:def", ""))
+	c, err = NewDiskCache(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"document:abc": "BQACAgIAAx"}, c.fileIDs)
+}
+
+func TestDiskCacheInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filecache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file_ids.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("not json"), 0600))
+
+	_, err = NewDiskCache(path)
+	assert.Error(t, err)
+}